@@ -0,0 +1,64 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/topi314/pogo-icons/internal/icongen"
+)
+
+// inspect prints the metadata embedded into the given icons.
+func inspect(args []string) {
+	fs := flag.NewFlagSet("inspect", flag.ExitOnError)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(), "Usage: %s inspect [-json] icon.png...\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	asJSON := fs.Bool("json", false, "Print the metadata as JSON")
+	_ = fs.Parse(args)
+
+	if fs.NArg() == 0 {
+		fs.Usage()
+		os.Exit(2)
+	}
+
+	for i, name := range fs.Args() {
+		metadata, err := readMetadata(name)
+		if err != nil {
+			if errors.Is(err, icongen.ErrNoMetadata) {
+				slog.Error("Icon has no metadata", slog.String("file", name))
+			} else {
+				slog.Error("Error while reading metadata", slog.String("file", name), slog.Any("err", err))
+			}
+			continue
+		}
+
+		if *asJSON {
+			data, _ := json.MarshalIndent(metadata, "", "  ")
+			fmt.Println(string(data))
+			continue
+		}
+		if fs.NArg() > 1 {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("%s:\n", name)
+		}
+		fmt.Println(metadata)
+	}
+}
+
+func readMetadata(name string) (icongen.Metadata, error) {
+	file, err := os.Open(name)
+	if err != nil {
+		return icongen.Metadata{}, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	return icongen.ReadMetadata(file)
+}
@@ -8,6 +8,8 @@ import (
 	"log/slog"
 	"os"
 	"os/signal"
+	"runtime/debug"
+	"strconv"
 	"strings"
 
 	"github.com/BurntSushi/toml"
@@ -17,12 +19,23 @@ import (
 )
 
 func main() {
-	pokemon := flag.String("pokemon", "", "A list of Pokemon names or IDs (comma separated)")
+	if len(os.Args) > 1 && os.Args[1] == "inspect" {
+		inspect(os.Args[2:])
+		return
+	}
+
+	pokemon := flag.String("pokemon", "", "A list of Pokemon names or IDs (comma separated), append :female to use female sprites")
+	evolutionLine := flag.String("evolution-line", "", "Include the whole evolution line of this Pokemon")
 	event := flag.String("event", "", "Event name")
 	cosmetics := flag.String("cosmetics", "", "A list of cosmetics names (comma separated)")
 	endpoint := flag.String("endpoint", "https://pokeapi.co/api/v2", "PokeAPI endpoint URL (default: https://pokeapi.co/api/v2)")
 	assets := flag.String("assets", "assets", "Assets directory (default: assets)")
 	output := flag.String("output", "output.png", "Output file name (default: output.png)")
+	format := flag.String("format", "png", "Output format: png, gif or apng (default: png)")
+	shiny := flag.Bool("shiny", false, "Use shiny sprites")
+	style := flag.String("style", "", "Sprite style: official-artwork, home, showdown, dream-world or pixel (default: showdown for animated formats, official-artwork otherwise)")
+	size := flag.Int("size", 0, "Crop a square of this size from the center, e.g. 128 for emojis or 320 for stickers (default: full icon)")
+	maxBytes := flag.Int("max-bytes", 0, "Scale the icon down until it is at most this many bytes (default: no limit)")
 	flag.Parse()
 
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, os.Kill)
@@ -47,33 +60,73 @@ func main() {
 		return
 	}
 
-	var getPokemonImage = func(ctx context.Context, p string) (io.ReadCloser, error) {
-		pf, err := pokeClient.GetPokemonForm(ctx, p)
+	outputFormat := icongen.Format(*format)
+	spriteStyle := pokeapi.SpriteStyle(*style)
+	if spriteStyle == "" {
+		spriteStyle = pokeapi.SpriteStyleOfficialArtwork
+		if outputFormat.Animated() {
+			spriteStyle = pokeapi.SpriteStyleShowdown
+		}
+	}
+	var getPokemon = func(ctx context.Context, p string) (*icongen.Pokemon, error) {
+		name, female := pokeapi.ParsePokemonName(p)
+		pf, err := pokeClient.GetPokemonForm(ctx, name)
 		if err != nil {
 			return nil, err
 		}
 
-		pokemonImage, err := pokeClient.GetSprite(ctx, pf.Sprite)
+		pokemonImage, err := pokeClient.GetSprite(ctx, pf.SpriteURL(spriteStyle, female, *shiny))
 		if err != nil {
 			return nil, err
 		}
 
-		return pokemonImage.Body, nil
+		return &icongen.Pokemon{
+			Image:  pokemonImage.Body,
+			Types:  pf.Types,
+			Height: pf.Height,
+		}, nil
 	}
 
 	var pokemonList []string
+	if *evolutionLine != "" {
+		forms, err := pokeClient.GetEvolutionLine(ctx, *evolutionLine)
+		if err != nil {
+			slog.ErrorContext(ctx, "Error while getting evolution line", slog.Any("err", err))
+			return
+		}
+		for _, form := range forms {
+			pokemonList = append(pokemonList, form.Value)
+		}
+	}
 	if *pokemon != "" {
-		pokemonList = strings.Split(*pokemon, ",")
+		pokemonList = append(pokemonList, strings.Split(*pokemon, ",")...)
+	}
+	var cosmeticList []string
+	if *cosmetics != "" {
+		cosmeticList = strings.Split(*cosmetics, ",")
+	}
+
+	version := "unknown"
+	if info, ok := debug.ReadBuildInfo(); ok {
+		version = info.Main.Version
 	}
-	cosmeticList := strings.Split(*cosmetics, ",")
 
-	r, err := icongen.Generate(ctx, assetsDir, cfg, getPokemonImage, *event, pokemonList, cosmeticList)
+	r, err := icongen.Generate(ctx, assetsDir, cfg, getPokemon, *event, pokemonList, cosmeticList, icongen.Options{
+		Format:   outputFormat,
+		Version:  version,
+		Size:     *size,
+		MaxBytes: *maxBytes,
+		Metadata: map[string]string{
+			"style": string(spriteStyle),
+			"shiny": strconv.FormatBool(*shiny),
+		},
+	})
 	if err != nil {
 		slog.ErrorContext(ctx, "Error while generating image", slog.Any("err", err))
 		return
 	}
 
-	outputFile, err := os.OpenFile("output.png", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+	outputFile, err := os.OpenFile(*output, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
 	if err != nil {
 		slog.ErrorContext(ctx, "error opening output file", slog.String("err", err.Error()))
 		return
@@ -7,10 +7,14 @@ require (
 	github.com/charmbracelet/log v1.0.0
 	github.com/disgoorg/disgo v0.19.3
 	github.com/disgoorg/json v1.2.0
+	github.com/disgoorg/omit v1.0.0
 	github.com/disgoorg/snowflake/v2 v2.0.3
 	github.com/go-git/go-billy/v5 v5.9.0
 	github.com/go-git/go-git/v5 v5.19.1
 	github.com/muesli/termenv v0.16.0
+	github.com/srwiley/oksvg v0.0.0-20221011165216-be6e8873101c
+	github.com/srwiley/rasterx v0.0.0-20210519020934-456a8d69b780
+	go.etcd.io/bbolt v1.4.3
 	go.gopad.dev/fuzzysearch v0.0.0-20240526153819-c12185e04fe2
 	golang.org/x/image v0.41.0
 )
@@ -31,7 +35,6 @@ require (
 	github.com/cyphar/filepath-securejoin v0.6.1 // indirect
 	github.com/disgoorg/godave v0.1.0 // indirect
 	github.com/disgoorg/json/v2 v2.0.0 // indirect
-	github.com/disgoorg/omit v1.0.0 // indirect
 	github.com/emirpasic/gods v1.18.1 // indirect
 	github.com/go-git/gcfg v1.5.1-0.20230307220236-3a3c6141e376 // indirect
 	github.com/go-logfmt/logfmt v0.6.1 // indirect
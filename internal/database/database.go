@@ -0,0 +1,149 @@
+package database
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+var ErrNotFound = errors.New("not found")
+
+var (
+	generationsBucket     = []byte("generations")
+	userGenerationsBucket = []byte("user_generations")
+)
+
+func Open(path string) (*DB, error) {
+	db, err := bolt.Open(path, 0o600, &bolt.Options{
+		Timeout: 5 * time.Second,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{generationsBucket, userGenerationsBucket, presetsBucket, guildConfigsBucket, guildAssetsBucket, guildAssetImagesBucket, usageBucket} {
+			if _, err = tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create buckets: %w", err)
+	}
+
+	return &DB{db: db}, nil
+}
+
+// DB stores the generated icons in a bbolt database.
+type DB struct {
+	db *bolt.DB
+}
+
+func (d *DB) Close() error {
+	return d.db.Close()
+}
+
+// Generation is a generated icon with everything needed to generate it again.
+type Generation struct {
+	ID        uint64       `json:"id"`
+	UserID    snowflake.ID `json:"user_id"`
+	GuildID   snowflake.ID `json:"guild_id,omitempty"`
+	Event     string       `json:"event"`
+	Pokemon   []string     `json:"pokemon"`
+	Cosmetics []string     `json:"cosmetics"`
+	Format    string       `json:"format"`
+	Style     string       `json:"style"`
+	Shiny     bool         `json:"shiny,omitempty"`
+	// Hash is the hex encoded SHA-256 hash of the generated icon.
+	Hash      string    `json:"hash"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AddGeneration stores the generation and returns it with its new ID.
+func (d *DB) AddGeneration(g Generation) (Generation, error) {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		generations := tx.Bucket(generationsBucket)
+		id, err := generations.NextSequence()
+		if err != nil {
+			return err
+		}
+		g.ID = id
+
+		data, err := json.Marshal(g)
+		if err != nil {
+			return err
+		}
+		if err = generations.Put(itob(id), data); err != nil {
+			return err
+		}
+
+		userGenerations, err := tx.Bucket(userGenerationsBucket).CreateBucketIfNotExists(itob(uint64(g.UserID)))
+		if err != nil {
+			return err
+		}
+		return userGenerations.Put(itob(id), nil)
+	})
+	if err != nil {
+		return Generation{}, fmt.Errorf("failed to add generation: %w", err)
+	}
+	return g, nil
+}
+
+// Generation returns the generation with the given ID.
+func (d *DB) Generation(id uint64) (Generation, error) {
+	var g Generation
+	err := d.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(generationsBucket).Get(itob(id))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &g)
+	})
+	if err != nil {
+		return Generation{}, fmt.Errorf("failed to get generation: %w", err)
+	}
+	return g, nil
+}
+
+// UserGenerations returns up to limit generations of the user, newest first.
+func (d *DB) UserGenerations(userID snowflake.ID, limit int) ([]Generation, error) {
+	var generations []Generation
+	err := d.db.View(func(tx *bolt.Tx) error {
+		userGenerations := tx.Bucket(userGenerationsBucket).Bucket(itob(uint64(userID)))
+		if userGenerations == nil {
+			return nil
+		}
+
+		bucket := tx.Bucket(generationsBucket)
+		c := userGenerations.Cursor()
+		for k, _ := c.Last(); k != nil && len(generations) < limit; k, _ = c.Prev() {
+			data := bucket.Get(k)
+			if data == nil {
+				continue
+			}
+			var g Generation
+			if err := json.Unmarshal(data, &g); err != nil {
+				return err
+			}
+			generations = append(generations, g)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user generations: %w", err)
+	}
+	return generations, nil
+}
+
+func itob(v uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, v)
+	return b
+}
@@ -0,0 +1,74 @@
+package database
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+)
+
+func openTestDB(t *testing.T) *DB {
+	t.Helper()
+	db, err := Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+	return db
+}
+
+func TestGenerations(t *testing.T) {
+	db := openTestDB(t)
+
+	var ids []uint64
+	for i, pokemon := range []string{"bulbasaur", "charmander", "squirtle"} {
+		g, err := db.AddGeneration(Generation{
+			UserID:    1,
+			Event:     "Community Day",
+			Pokemon:   []string{pokemon},
+			Cosmetics: []string{"Party Hat"},
+			Format:    "png",
+			Style:     "home",
+			Shiny:     i == 0,
+			Hash:      "hash",
+			CreatedAt: time.Now().Truncate(time.Second),
+		})
+		if err != nil {
+			t.Fatalf("failed to add generation: %v", err)
+		}
+		ids = append(ids, g.ID)
+	}
+	if _, err := db.AddGeneration(Generation{UserID: 2, Pokemon: []string{"pikachu"}}); err != nil {
+		t.Fatalf("failed to add generation: %v", err)
+	}
+
+	g, err := db.Generation(ids[0])
+	if err != nil {
+		t.Fatalf("failed to get generation: %v", err)
+	}
+	if g.UserID != 1 || g.Event != "Community Day" || !slices.Equal(g.Pokemon, []string{"bulbasaur"}) || !slices.Equal(g.Cosmetics, []string{"Party Hat"}) || !g.Shiny || g.Style != "home" {
+		t.Fatalf("unexpected generation: %+v", g)
+	}
+	if _, err = db.Generation(1000); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got error %v, want ErrNotFound", err)
+	}
+
+	// the generations of other users are not included
+	generations, err := db.UserGenerations(1, 2)
+	if err != nil {
+		t.Fatalf("failed to get user generations: %v", err)
+	}
+	var got []uint64
+	for _, g = range generations {
+		got = append(got, g.ID)
+	}
+	if want := []uint64{ids[2], ids[1]}; !slices.Equal(got, want) {
+		t.Fatalf("got generations %v, want newest first %v", got, want)
+	}
+	if generations, err = db.UserGenerations(3, 10); err != nil || len(generations) != 0 {
+		t.Fatalf("got generations %v, %v for user without generations, want none", generations, err)
+	}
+}
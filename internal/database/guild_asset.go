@@ -0,0 +1,151 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	guildAssetsBucket = []byte("guild_assets")
+	// guildAssetImagesBucket stores the images separately, so listing the assets doesn't load them.
+	guildAssetImagesBucket = []byte("guild_asset_images")
+)
+
+// GuildAsset is an image uploaded by a guild which can be used as cosmetic on the icons generated in it.
+type GuildAsset struct {
+	Name    string       `json:"name"`
+	GuildID snowflake.ID `json:"guild_id"`
+	// Position is the position of the image on the icon.
+	Position string `json:"position"`
+	// Scale is the height of the image relative to the icon.
+	Scale float64 `json:"scale"`
+	// Slot is the slot of the cosmetic, see icongen.CosmeticConfig.
+	Slot string `json:"slot,omitempty"`
+	// Image is the PNG encoded image, it is only loaded by GuildAssetImage.
+	Image      []byte       `json:"-"`
+	UploadedBy snowflake.ID `json:"uploaded_by"`
+	UploadedAt time.Time    `json:"uploaded_at"`
+}
+
+func guildAssetKey(name string) []byte {
+	return []byte(strings.ToLower(name))
+}
+
+// SaveGuildAsset creates or replaces the asset with the same name of the guild.
+func (d *DB) SaveGuildAsset(a GuildAsset) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		assets, err := tx.Bucket(guildAssetsBucket).CreateBucketIfNotExists(itob(uint64(a.GuildID)))
+		if err != nil {
+			return err
+		}
+
+		images, err := tx.Bucket(guildAssetImagesBucket).CreateBucketIfNotExists(itob(uint64(a.GuildID)))
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(a)
+		if err != nil {
+			return err
+		}
+		if err = assets.Put(guildAssetKey(a.Name), data); err != nil {
+			return err
+		}
+		return images.Put(guildAssetKey(a.Name), a.Image)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save guild asset: %w", err)
+	}
+	return nil
+}
+
+// GuildAsset returns the asset of the guild with the given name, names are case-insensitive.
+func (d *DB) GuildAsset(guildID snowflake.ID, name string) (GuildAsset, error) {
+	var a GuildAsset
+	err := d.db.View(func(tx *bolt.Tx) error {
+		assets := tx.Bucket(guildAssetsBucket).Bucket(itob(uint64(guildID)))
+		if assets == nil {
+			return ErrNotFound
+		}
+		data := assets.Get(guildAssetKey(name))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &a)
+	})
+	if err != nil {
+		return GuildAsset{}, fmt.Errorf("failed to get guild asset: %w", err)
+	}
+	return a, nil
+}
+
+// GuildAssetImage returns the image of the asset of the guild with the given name.
+func (d *DB) GuildAssetImage(guildID snowflake.ID, name string) ([]byte, error) {
+	var image []byte
+	err := d.db.View(func(tx *bolt.Tx) error {
+		images := tx.Bucket(guildAssetImagesBucket).Bucket(itob(uint64(guildID)))
+		if images == nil {
+			return ErrNotFound
+		}
+		data := images.Get(guildAssetKey(name))
+		if data == nil {
+			return ErrNotFound
+		}
+		// data is only valid during the transaction
+		image = append([]byte(nil), data...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild asset image: %w", err)
+	}
+	return image, nil
+}
+
+// GuildAssets returns all assets of the guild ordered by name.
+func (d *DB) GuildAssets(guildID snowflake.ID) ([]GuildAsset, error) {
+	var assets []GuildAsset
+	err := d.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(guildAssetsBucket).Bucket(itob(uint64(guildID)))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_ []byte, data []byte) error {
+			var a GuildAsset
+			if err := json.Unmarshal(data, &a); err != nil {
+				return err
+			}
+			assets = append(assets, a)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get guild assets: %w", err)
+	}
+	return assets, nil
+}
+
+// DeleteGuildAsset deletes the asset of the guild with the given name.
+func (d *DB) DeleteGuildAsset(guildID snowflake.ID, name string) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		assets := tx.Bucket(guildAssetsBucket).Bucket(itob(uint64(guildID)))
+		if assets == nil || assets.Get(guildAssetKey(name)) == nil {
+			return ErrNotFound
+		}
+		if err := assets.Delete(guildAssetKey(name)); err != nil {
+			return err
+		}
+		if images := tx.Bucket(guildAssetImagesBucket).Bucket(itob(uint64(guildID))); images != nil {
+			return images.Delete(guildAssetKey(name))
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete guild asset: %w", err)
+	}
+	return nil
+}
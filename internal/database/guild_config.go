@@ -0,0 +1,71 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+var guildConfigsBucket = []byte("guild_configs")
+
+// GuildConfig are the settings of a guild for the icons generated in it. The zero value are the default settings.
+type GuildConfig struct {
+	GuildID snowflake.ID `json:"guild_id"`
+	// DefaultEvent is used if no event is given.
+	DefaultEvent string `json:"default_event,omitempty"`
+	// AllowedEvents are the only events which can be used, empty allows all events.
+	AllowedEvents []string `json:"allowed_events,omitempty"`
+	// AllowedCosmetics are the only cosmetics which can be used, empty allows all cosmetics.
+	AllowedCosmetics []string `json:"allowed_cosmetics,omitempty"`
+	// BrandingPosition is the position the guild icon is drawn at on every icon, empty disables the branding.
+	BrandingPosition string `json:"branding_position,omitempty"`
+	// Width is the width icons are scaled down to, 0 keeps the full size.
+	Width     int          `json:"width,omitempty"`
+	UpdatedBy snowflake.ID `json:"updated_by"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// GuildConfig returns the config of the guild or ErrNotFound if the guild uses the default settings.
+func (d *DB) GuildConfig(guildID snowflake.ID) (GuildConfig, error) {
+	var c GuildConfig
+	err := d.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(guildConfigsBucket).Get(itob(uint64(guildID)))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &c)
+	})
+	if err != nil {
+		return GuildConfig{}, fmt.Errorf("failed to get guild config: %w", err)
+	}
+	return c, nil
+}
+
+// SaveGuildConfig creates or replaces the config of the guild.
+func (d *DB) SaveGuildConfig(c GuildConfig) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		data, err := json.Marshal(c)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(guildConfigsBucket).Put(itob(uint64(c.GuildID)), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save guild config: %w", err)
+	}
+	return nil
+}
+
+// DeleteGuildConfig resets the guild to the default settings.
+func (d *DB) DeleteGuildConfig(guildID snowflake.ID) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(guildConfigsBucket).Delete(itob(uint64(guildID)))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete guild config: %w", err)
+	}
+	return nil
+}
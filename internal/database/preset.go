@@ -0,0 +1,118 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+var presetsBucket = []byte("presets")
+
+type PresetScope string
+
+const (
+	PresetScopeUser  PresetScope = "user"
+	PresetScopeGuild PresetScope = "guild"
+)
+
+// Preset is a named combination of generate parameters saved by a user for themselves or for a guild.
+type Preset struct {
+	Name  string      `json:"name"`
+	Scope PresetScope `json:"scope"`
+	// OwnerID is the ID of the user or guild the preset belongs to.
+	OwnerID   snowflake.ID `json:"owner_id"`
+	CreatedBy snowflake.ID `json:"created_by"`
+	Event     string       `json:"event"`
+	Pokemon   []string     `json:"pokemon"`
+	Cosmetics []string     `json:"cosmetics"`
+	Format    string       `json:"format"`
+	Style     string       `json:"style"`
+	Shiny     bool         `json:"shiny,omitempty"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+func presetKey(name string) []byte {
+	return []byte(strings.ToLower(name))
+}
+
+// SavePreset creates or replaces the preset with the same name of the owner.
+func (d *DB) SavePreset(p Preset) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		presets, err := tx.Bucket(presetsBucket).CreateBucketIfNotExists(itob(uint64(p.OwnerID)))
+		if err != nil {
+			return err
+		}
+
+		data, err := json.Marshal(p)
+		if err != nil {
+			return err
+		}
+		return presets.Put(presetKey(p.Name), data)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save preset: %w", err)
+	}
+	return nil
+}
+
+// Preset returns the preset of the owner with the given name, names are case-insensitive.
+func (d *DB) Preset(ownerID snowflake.ID, name string) (Preset, error) {
+	var p Preset
+	err := d.db.View(func(tx *bolt.Tx) error {
+		presets := tx.Bucket(presetsBucket).Bucket(itob(uint64(ownerID)))
+		if presets == nil {
+			return ErrNotFound
+		}
+		data := presets.Get(presetKey(name))
+		if data == nil {
+			return ErrNotFound
+		}
+		return json.Unmarshal(data, &p)
+	})
+	if err != nil {
+		return Preset{}, fmt.Errorf("failed to get preset: %w", err)
+	}
+	return p, nil
+}
+
+// Presets returns all presets of the owner ordered by name.
+func (d *DB) Presets(ownerID snowflake.ID) ([]Preset, error) {
+	var presets []Preset
+	err := d.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(presetsBucket).Bucket(itob(uint64(ownerID)))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(_ []byte, data []byte) error {
+			var p Preset
+			if err := json.Unmarshal(data, &p); err != nil {
+				return err
+			}
+			presets = append(presets, p)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get presets: %w", err)
+	}
+	return presets, nil
+}
+
+// DeletePreset deletes the preset of the owner with the given name.
+func (d *DB) DeletePreset(ownerID snowflake.ID, name string) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		presets := tx.Bucket(presetsBucket).Bucket(itob(uint64(ownerID)))
+		if presets == nil || presets.Get(presetKey(name)) == nil {
+			return ErrNotFound
+		}
+		return presets.Delete(presetKey(name))
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete preset: %w", err)
+	}
+	return nil
+}
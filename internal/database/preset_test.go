@@ -0,0 +1,77 @@
+package database
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPresets(t *testing.T) {
+	db := openTestDB(t)
+
+	userPreset := Preset{
+		Name:      "Spotlight",
+		Scope:     PresetScopeUser,
+		OwnerID:   1,
+		CreatedBy: 1,
+		Event:     "Spotlight Hour",
+		Pokemon:   []string{"pikachu"},
+		Cosmetics: []string{"Party Hat"},
+		Format:    "png",
+		Style:     "home",
+		Shiny:     true,
+	}
+	guildPreset := Preset{
+		Name:      "Spotlight",
+		Scope:     PresetScopeGuild,
+		OwnerID:   2,
+		CreatedBy: 1,
+		Event:     "Community Day",
+	}
+	for _, p := range []Preset{userPreset, guildPreset} {
+		if err := db.SavePreset(p); err != nil {
+			t.Fatalf("failed to save preset: %v", err)
+		}
+	}
+
+	// names are case-insensitive
+	p, err := db.Preset(1, "spotlight")
+	if err != nil {
+		t.Fatalf("failed to get preset: %v", err)
+	}
+	if p.Event != userPreset.Event || !slices.Equal(p.Pokemon, userPreset.Pokemon) || !slices.Equal(p.Cosmetics, userPreset.Cosmetics) || !p.Shiny || p.Scope != PresetScopeUser {
+		t.Fatalf("unexpected preset: %+v", p)
+	}
+
+	// presets of the guild are separate from the presets of the user
+	if p, err = db.Preset(2, "Spotlight"); err != nil || p.Event != guildPreset.Event {
+		t.Fatalf("got guild preset %+v, %v", p, err)
+	}
+	if _, err = db.Preset(3, "Spotlight"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got error %v, want ErrNotFound", err)
+	}
+
+	// saving a preset with the same name replaces it
+	userPreset.Name = "SPOTLIGHT"
+	userPreset.Event = "Raid Day"
+	if err = db.SavePreset(userPreset); err != nil {
+		t.Fatalf("failed to save preset: %v", err)
+	}
+	presets, err := db.Presets(1)
+	if err != nil {
+		t.Fatalf("failed to get presets: %v", err)
+	}
+	if len(presets) != 1 || presets[0].Event != "Raid Day" {
+		t.Fatalf("got presets %+v, want the replaced preset", presets)
+	}
+
+	if err = db.DeletePreset(1, "spotlight"); err != nil {
+		t.Fatalf("failed to delete preset: %v", err)
+	}
+	if err = db.DeletePreset(1, "spotlight"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("got error %v, want ErrNotFound", err)
+	}
+	if _, err = db.Preset(2, "spotlight"); err != nil {
+		t.Fatalf("expected the guild preset to be kept: %v", err)
+	}
+}
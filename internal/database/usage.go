@@ -0,0 +1,104 @@
+package database
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+	bolt "go.etcd.io/bbolt"
+)
+
+// usageBucket contains a bucket per user or guild which maps the Pokémon to their Usage.
+var usageBucket = []byte("usage")
+
+// Usage is how often and when a Pokémon was last generated by a user or in a guild.
+type Usage struct {
+	Count    int       `json:"count"`
+	LastUsed time.Time `json:"last_used"`
+}
+
+// RecordUsage counts the Pokémon as generated in the scope. Only the limit most recently used Pokémon are kept.
+func (d *DB) RecordUsage(scope snowflake.ID, pokemon []string, now time.Time, limit int) error {
+	err := d.db.Update(func(tx *bolt.Tx) error {
+		used, err := tx.Bucket(usageBucket).CreateBucketIfNotExists(itob(uint64(scope)))
+		if err != nil {
+			return err
+		}
+		for _, p := range pokemon {
+			var u Usage
+			if data := used.Get([]byte(p)); data != nil {
+				if err = json.Unmarshal(data, &u); err != nil {
+					return err
+				}
+			}
+			u.Count++
+			u.LastUsed = now
+
+			data, err := json.Marshal(u)
+			if err != nil {
+				return err
+			}
+			if err = used.Put([]byte(p), data); err != nil {
+				return err
+			}
+		}
+
+		type entry struct {
+			pokemon  []byte
+			lastUsed time.Time
+		}
+		var entries []entry
+		if err = used.ForEach(func(k []byte, v []byte) error {
+			var u Usage
+			if err := json.Unmarshal(v, &u); err != nil {
+				return err
+			}
+			entries = append(entries, entry{pokemon: slices.Clone(k), lastUsed: u.LastUsed})
+			return nil
+		}); err != nil {
+			return err
+		}
+		if len(entries) <= limit {
+			return nil
+		}
+		// drop the least recently used
+		slices.SortFunc(entries, func(a entry, b entry) int {
+			return a.lastUsed.Compare(b.lastUsed)
+		})
+		for _, e := range entries[:len(entries)-limit] {
+			if err = used.Delete(e.pokemon); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record usage: %w", err)
+	}
+	return nil
+}
+
+// Usage returns the usage of the Pokémon generated in the scope.
+func (d *DB) Usage(scope snowflake.ID) (map[string]Usage, error) {
+	usage := make(map[string]Usage)
+	err := d.db.View(func(tx *bolt.Tx) error {
+		used := tx.Bucket(usageBucket).Bucket(itob(uint64(scope)))
+		if used == nil {
+			return nil
+		}
+		return used.ForEach(func(k []byte, v []byte) error {
+			var u Usage
+			if err := json.Unmarshal(v, &u); err != nil {
+				return err
+			}
+			usage[string(k)] = u
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get usage: %w", err)
+	}
+	return usage, nil
+}
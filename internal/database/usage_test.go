@@ -0,0 +1,41 @@
+package database
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUsage(t *testing.T) {
+	db := openTestDB(t)
+	now := time.Now()
+
+	if err := db.RecordUsage(1, []string{"pikachu", "pichu"}, now.Add(-2*time.Hour), 2); err != nil {
+		t.Fatalf("failed to record usage: %v", err)
+	}
+	if err := db.RecordUsage(1, []string{"pikachu"}, now.Add(-time.Hour), 2); err != nil {
+		t.Fatalf("failed to record usage: %v", err)
+	}
+	// raichu is over the limit, so the least recently used pichu is dropped
+	if err := db.RecordUsage(1, []string{"raichu"}, now, 2); err != nil {
+		t.Fatalf("failed to record usage: %v", err)
+	}
+
+	usage, err := db.Usage(1)
+	if err != nil {
+		t.Fatalf("failed to get usage: %v", err)
+	}
+	if len(usage) != 2 {
+		t.Fatalf("got %d pokemon, want 2: %v", len(usage), usage)
+	}
+	if u := usage["pikachu"]; u.Count != 2 || !u.LastUsed.Equal(now.Add(-time.Hour)) {
+		t.Fatalf("unexpected usage of pikachu: %+v", u)
+	}
+	if _, ok := usage["pichu"]; ok {
+		t.Fatal("expected pichu to be dropped")
+	}
+
+	// scopes are independent
+	if usage, err = db.Usage(2); err != nil || len(usage) != 0 {
+		t.Fatalf("got usage %v, %v for other scope, want none", usage, err)
+	}
+}
@@ -0,0 +1,111 @@
+package i18n
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+)
+
+// DefaultLocale is the locale used for keys which are missing in the catalog of a locale.
+const DefaultLocale = "en-US"
+
+// Catalog maps message keys like "commands.generate.description" to messages.
+type Catalog map[string]string
+
+// Translator translates message keys using the catalogs of all locales.
+type Translator struct {
+	catalogs map[string]Catalog
+}
+
+// Load reads all catalogs in dir. The file name of a catalog is its locale, e.g. "de.toml" or "es-ES.toml".
+func Load(assets fs.FS, dir string) (*Translator, error) {
+	entries, err := fs.ReadDir(assets, dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read locales: %w", err)
+	}
+
+	t := &Translator{
+		catalogs: make(map[string]Catalog, len(entries)),
+	}
+	for _, entry := range entries {
+		locale, ok := strings.CutSuffix(entry.Name(), ".toml")
+		if entry.IsDir() || !ok {
+			continue
+		}
+
+		data, err := fs.ReadFile(assets, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read locale %q: %w", locale, err)
+		}
+		var raw map[string]any
+		if err = toml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("failed to parse locale %q: %w", locale, err)
+		}
+		catalog := make(Catalog)
+		flatten(catalog, "", raw)
+		t.catalogs[locale] = catalog
+	}
+
+	if _, ok := t.catalogs[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("missing default locale %q", DefaultLocale)
+	}
+	return t, nil
+}
+
+// flatten joins the keys of nested tables with dots.
+func flatten(catalog Catalog, prefix string, raw map[string]any) {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flatten(catalog, key, v)
+		case string:
+			catalog[key] = v
+		}
+	}
+}
+
+// catalog returns the catalog of the locale or of another locale with the same language, e.g. "es-ES" for "es-419".
+func (t *Translator) catalog(locale string) (Catalog, bool) {
+	if catalog, ok := t.catalogs[locale]; ok {
+		return catalog, true
+	}
+	lang, _, _ := strings.Cut(locale, "-")
+	for l, catalog := range t.catalogs {
+		if other, _, _ := strings.Cut(l, "-"); other == lang {
+			return catalog, true
+		}
+	}
+	return nil, false
+}
+
+// Lookup returns the message of the key in the locale without falling back to the default locale.
+func (t *Translator) Lookup(locale string, key string) (string, bool) {
+	catalog, ok := t.catalog(locale)
+	if !ok {
+		return "", false
+	}
+	msg, ok := catalog[key]
+	return msg, ok
+}
+
+// Translate returns the message of the key in the locale formatted with args.
+// Missing messages fall back to the default locale and then to the key itself.
+func (t *Translator) Translate(locale string, key string, args ...any) string {
+	msg, ok := t.Lookup(locale, key)
+	if !ok {
+		msg, ok = t.catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		msg = key
+	}
+	if len(args) == 0 {
+		return msg
+	}
+	return fmt.Sprintf(msg, args...)
+}
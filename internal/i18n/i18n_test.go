@@ -0,0 +1,100 @@
+package i18n
+
+import (
+	"os"
+	"regexp"
+	"slices"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTranslate(t *testing.T) {
+	assets := fstest.MapFS{
+		"locales/en-US.toml": {Data: []byte(`
+[generate]
+success = "Generated %s"
+error = "Error"
+`)},
+		"locales/es-ES.toml": {Data: []byte(`
+[generate]
+success = "Generado %s"
+`)},
+		"locales/README.md": {Data: []byte("not a catalog")},
+	}
+	translator, err := Load(assets, "locales")
+	if err != nil {
+		t.Fatalf("failed to load catalogs: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		locale string
+		key    string
+		args   []any
+		want   string
+	}{
+		{name: "nested key", locale: "en-US", key: "generate.error", want: "Error"},
+		{name: "format", locale: "en-US", key: "generate.success", args: []any{"Pikachu"}, want: "Generated Pikachu"},
+		{name: "locale", locale: "es-ES", key: "generate.success", args: []any{"Pikachu"}, want: "Generado Pikachu"},
+		{name: "same language", locale: "es-419", key: "generate.success", args: []any{"Pikachu"}, want: "Generado Pikachu"},
+		{name: "missing key falls back to default locale", locale: "es-ES", key: "generate.error", want: "Error"},
+		{name: "missing locale falls back to default locale", locale: "ja", key: "generate.error", want: "Error"},
+		{name: "missing key", locale: "en-US", key: "generate.missing", want: "generate.missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := translator.Translate(tt.locale, tt.key, tt.args...); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, ok := translator.Lookup("es-ES", "generate.error"); ok {
+		t.Fatal("expected Lookup not to fall back to the default locale")
+	}
+}
+
+func TestLoadMissingDefaultLocale(t *testing.T) {
+	assets := fstest.MapFS{
+		"locales/de.toml": {Data: []byte(`key = "Wert"`)},
+	}
+	if _, err := Load(assets, "locales"); err == nil {
+		t.Fatal("expected error for missing default locale")
+	}
+}
+
+// formatVerbRegex matches the fmt verbs of a message.
+var formatVerbRegex = regexp.MustCompile(`%[sdvqf]`)
+
+// TestCatalogs checks that every catalog in the assets has the same keys and format verbs as the default locale.
+func TestCatalogs(t *testing.T) {
+	translator, err := Load(os.DirFS("../../assets"), "locales")
+	if err != nil {
+		t.Fatalf("failed to load catalogs: %v", err)
+	}
+
+	defaultCatalog := translator.catalogs[DefaultLocale]
+	for locale, catalog := range translator.catalogs {
+		t.Run(locale, func(t *testing.T) {
+			for key, msg := range defaultCatalog {
+				translated, ok := catalog[key]
+				if !ok {
+					t.Errorf("missing key %q", key)
+					continue
+				}
+				want := formatVerbRegex.FindAllString(msg, -1)
+				slices.Sort(want)
+				got := formatVerbRegex.FindAllString(translated, -1)
+				slices.Sort(got)
+				if !slices.Equal(got, want) {
+					t.Errorf("key %q has format verbs %v, want %v", key, got, want)
+				}
+			}
+			for key := range catalog {
+				if _, ok := defaultCatalog[key]; !ok {
+					t.Errorf("unknown key %q", key)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,413 @@
+package icongen
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/gif"
+	"io"
+	"slices"
+)
+
+const (
+	defaultFrames = 24
+	defaultFPS    = 12
+)
+
+type Format string
+
+const (
+	FormatPNG  Format = "png"
+	FormatGIF  Format = "gif"
+	FormatAPNG Format = "apng"
+)
+
+// Animated returns whether the format supports multiple frames.
+func (f Format) Animated() bool {
+	return f == FormatGIF || f == FormatAPNG
+}
+
+// Extension returns the file extension of the format without the leading dot.
+func (f Format) Extension() string {
+	if f == FormatGIF {
+		return "gif"
+	}
+	return "png"
+}
+
+type AnimationConfig struct {
+	// Frames is the number of frames of the animation.
+	// Defaults to 24.
+	Frames int `toml:"frames"`
+	// FPS is the number of frames shown per second.
+	// Defaults to 12.
+	FPS int `toml:"fps"`
+}
+
+// Keyframe describes the state of an animated layer at a point in the animation.
+// Values are interpolated linearly between keyframes.
+type Keyframe struct {
+	// Time is the position of the keyframe in the animation from 0.0 to 1.0.
+	Time float64 `toml:"time"`
+	// OffsetX is added to the x offset of the layer.
+	OffsetX float64 `toml:"offset_x"`
+	// OffsetY is added to the y offset of the layer.
+	OffsetY float64 `toml:"offset_y"`
+	// Scale is multiplied with the size of the layer.
+	// Defaults to 1.0.
+	Scale *float64 `toml:"scale"`
+	// Rotate is added to the rotation of the layer in degrees.
+	Rotate float64 `toml:"rotate"`
+	// Opacity is the opacity of the layer from 0.0 to 1.0.
+	// Defaults to 1.0.
+	Opacity *float64 `toml:"opacity"`
+}
+
+type frameState struct {
+	offsetX float64
+	offsetY float64
+	scale   float64
+	rotate  float64
+	opacity float64
+}
+
+func (k Keyframe) state() frameState {
+	s := frameState{
+		offsetX: k.OffsetX,
+		offsetY: k.OffsetY,
+		scale:   1,
+		rotate:  k.Rotate,
+		opacity: 1,
+	}
+	if k.Scale != nil {
+		s.scale = *k.Scale
+	}
+	if k.Opacity != nil {
+		s.opacity = *k.Opacity
+	}
+	return s
+}
+
+// stateAt interpolates the keyframes at time t.
+func stateAt(keyframes []Keyframe, t float64) frameState {
+	if len(keyframes) == 0 {
+		return Keyframe{}.state()
+	}
+
+	keyframes = slices.SortedStableFunc(slices.Values(keyframes), func(a Keyframe, b Keyframe) int {
+		switch {
+		case a.Time < b.Time:
+			return -1
+		case a.Time > b.Time:
+			return 1
+		default:
+			return 0
+		}
+	})
+
+	if t <= keyframes[0].Time {
+		return keyframes[0].state()
+	}
+	for i := 1; i < len(keyframes); i++ {
+		next := keyframes[i]
+		if t > next.Time {
+			continue
+		}
+		prev := keyframes[i-1]
+		p := (t - prev.Time) / (next.Time - prev.Time)
+		a, b := prev.state(), next.state()
+		lerp := func(a float64, b float64) float64 {
+			return a + (b-a)*p
+		}
+		return frameState{
+			offsetX: lerp(a.offsetX, b.offsetX),
+			offsetY: lerp(a.offsetY, b.offsetY),
+			scale:   lerp(a.scale, b.scale),
+			rotate:  lerp(a.rotate, b.rotate),
+			opacity: lerp(a.opacity, b.opacity),
+		}
+	}
+	return keyframes[len(keyframes)-1].state()
+}
+
+// decodeSprite decodes a Pokémon sprite. Animated GIFs are decoded into all of their frames and SVGs are rasterized.
+func decodeSprite(r io.ReadCloser, name string) (imageLayer, error) {
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return imageLayer{}, fmt.Errorf("failed to read image %q: %w", name, err)
+	}
+
+	if isSVG(data) {
+		img, err := decodeSVG(data)
+		if err != nil {
+			return imageLayer{}, fmt.Errorf("failed to decode image %q: %w", name, err)
+		}
+		return imageLayer{
+			Image: img,
+		}, nil
+	}
+
+	if !bytes.HasPrefix(data, []byte("GIF8")) {
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return imageLayer{}, fmt.Errorf("failed to decode image %q: %w", name, err)
+		}
+		return imageLayer{
+			Image: img,
+		}, nil
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(data))
+	if err != nil {
+		return imageLayer{}, fmt.Errorf("failed to decode gif %q: %w", name, err)
+	}
+	frames := composeGIF(g)
+	if len(frames) == 1 {
+		return imageLayer{
+			Image: frames[0],
+		}, nil
+	}
+	return imageLayer{
+		Image:  frames[0],
+		Frames: frames,
+		Delays: g.Delay,
+	}, nil
+}
+
+// composeGIF draws the frames of a GIF onto each other as a viewer would and returns the full frames.
+func composeGIF(g *gif.GIF) []image.Image {
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		bounds = g.Image[0].Bounds()
+	}
+	canvas := image.NewRGBA(bounds)
+	frames := make([]image.Image, 0, len(g.Image))
+	for i, img := range g.Image {
+		var previous *image.RGBA
+		disposal := byte(gif.DisposalNone)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, img.Bounds(), img, img.Bounds().Min, draw.Over)
+		frame := image.NewRGBA(bounds)
+		copy(frame.Pix, canvas.Pix)
+		frames = append(frames, frame)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, img.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+	return frames
+}
+
+// gifDelay returns the delay of a GIF frame, viewers show frames without a delay for 1/10s.
+func gifDelay(delay int) int {
+	if delay <= 1 {
+		return 10
+	}
+	return delay
+}
+
+// loopDuration returns the duration of one loop of the frames in 1/100s.
+func loopDuration(delays []int) int {
+	var total int
+	for _, delay := range delays {
+		total += gifDelay(delay)
+	}
+	return total
+}
+
+// frameAt returns the index of the frame shown at elapsed 1/100s.
+func frameAt(delays []int, elapsed int) int {
+	loop := loopDuration(delays)
+	if loop == 0 {
+		return 0
+	}
+	elapsed %= loop
+	for i, delay := range delays {
+		elapsed -= gifDelay(delay)
+		if elapsed < 0 {
+			return i
+		}
+	}
+	return len(delays) - 1
+}
+
+func encodeGIF(w io.Writer, frames []*image.RGBA, fps int) error {
+	var pixels []color.NRGBA
+	for _, frame := range frames {
+		pixels = append(pixels, samplePixels(frame, paletteSamples/len(frames), func(c color.NRGBA) bool {
+			return c.A >= 0x80
+		})...)
+	}
+
+	palette := color.Palette{color.Transparent}
+	for _, c := range medianCut(pixels, 255) {
+		palette = append(palette, c)
+	}
+
+	bounds := frames[0].Bounds()
+	anim := &gif.GIF{
+		LoopCount: 0,
+		// share the palette between all frames instead of repeating it for every frame
+		Config: image.Config{
+			ColorModel: palette,
+			Width:      bounds.Dx(),
+			Height:     bounds.Dy(),
+		},
+	}
+	delays := gifDelays(len(frames), fps)
+	cache := make(map[color.RGBA]uint8)
+	for i, frame := range frames {
+		anim.Image = append(anim.Image, quantize(frame, palette, cache))
+		anim.Delay = append(anim.Delay, delays[i])
+		anim.Disposal = append(anim.Disposal, gif.DisposalBackground)
+	}
+
+	if err := gif.EncodeAll(w, anim); err != nil {
+		return fmt.Errorf("failed to encode gif: %w", err)
+	}
+	return nil
+}
+
+// gifDelays returns the delays of the frames in 1/100s. GIF delays can't represent every frame rate, so they alternate
+// to keep the total duration at frames/fps, e.g. 8, 8, 9 for 12 fps. Delays below 2 are raised as viewers slow them
+// down to 1/10s.
+func gifDelays(frames int, fps int) []int {
+	delays := make([]int, 0, frames)
+	for i := range frames {
+		delays = append(delays, max((i+1)*100/fps-i*100/fps, 2))
+	}
+	return delays
+}
+
+// quantize maps every pixel of the frame to the closest palette color without dithering,
+// dithering changes between frames and makes the animation flicker.
+func quantize(frame *image.RGBA, palette color.Palette, cache map[color.RGBA]uint8) *image.Paletted {
+	bounds := frame.Bounds()
+	paletted := image.NewPaletted(bounds, palette)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := frame.RGBAAt(x, y)
+			// drop the lowest bits to keep the cache small
+			key := color.RGBA{R: c.R &^ 3, G: c.G &^ 3, B: c.B &^ 3, A: c.A &^ 3}
+			i, ok := cache[key]
+			if !ok {
+				i = uint8(palette.Index(c))
+				cache[key] = i
+			}
+			paletted.SetColorIndex(x, y, i)
+		}
+	}
+	return paletted
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// encodeAPNG writes the frames as an animated PNG. Every frame is written as a full 8-bit RGBA image.
+func encodeAPNG(w io.Writer, frames []*image.RGBA, fps int) error {
+	bounds := frames[0].Bounds()
+	buf := new(bytes.Buffer)
+	buf.Write(pngSignature)
+
+	ihdr := make([]byte, 13)
+	binary.BigEndian.PutUint32(ihdr[0:], uint32(bounds.Dx()))
+	binary.BigEndian.PutUint32(ihdr[4:], uint32(bounds.Dy()))
+	ihdr[8] = 8 // bit depth
+	ihdr[9] = 6 // color type RGBA
+	writeChunk(buf, "IHDR", ihdr)
+
+	actl := make([]byte, 8)
+	binary.BigEndian.PutUint32(actl[0:], uint32(len(frames)))
+	binary.BigEndian.PutUint32(actl[4:], 0) // loop forever
+	writeChunk(buf, "acTL", actl)
+
+	var seq uint32
+	for i, frame := range frames {
+		fctl := make([]byte, 26)
+		binary.BigEndian.PutUint32(fctl[0:], seq)
+		binary.BigEndian.PutUint32(fctl[4:], uint32(bounds.Dx()))
+		binary.BigEndian.PutUint32(fctl[8:], uint32(bounds.Dy()))
+		binary.BigEndian.PutUint16(fctl[20:], 1)
+		binary.BigEndian.PutUint16(fctl[22:], uint16(fps))
+		writeChunk(buf, "fcTL", fctl)
+		seq++
+
+		data, err := compressFrame(frame)
+		if err != nil {
+			return fmt.Errorf("failed to compress frame %d: %w", i, err)
+		}
+		if i == 0 {
+			writeChunk(buf, "IDAT", data)
+			continue
+		}
+		fdat := make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(fdat, seq)
+		writeChunk(buf, "fdAT", append(fdat, data...))
+		seq++
+	}
+	writeChunk(buf, "IEND", nil)
+
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func writeChunk(buf *bytes.Buffer, name string, data []byte) {
+	header := make([]byte, 8)
+	binary.BigEndian.PutUint32(header, uint32(len(data)))
+	copy(header[4:], name)
+	buf.Write(header)
+	buf.Write(data)
+
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	_ = binary.Write(buf, binary.BigEndian, crc.Sum32())
+}
+
+// compressFrame returns the zlib compressed scanlines of the frame using the sub filter.
+func compressFrame(frame *image.RGBA) ([]byte, error) {
+	bounds := frame.Bounds()
+	nrgba := image.NewNRGBA(bounds)
+	draw.Draw(nrgba, bounds, frame, bounds.Min, draw.Src)
+
+	buf := new(bytes.Buffer)
+	zw, err := zlib.NewWriterLevel(buf, zlib.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+	stride := bounds.Dx() * 4
+	line := make([]byte, 1+stride)
+	line[0] = 1 // sub filter
+	for y := range bounds.Dy() {
+		row := nrgba.Pix[y*nrgba.Stride : y*nrgba.Stride+stride]
+		for x := range stride {
+			var left byte
+			if x >= 4 {
+				left = row[x-4]
+			}
+			line[1+x] = row[x] - left
+		}
+		if _, err = zw.Write(line); err != nil {
+			return nil, err
+		}
+	}
+	if err = zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
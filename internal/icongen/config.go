@@ -1,23 +1,140 @@
 package icongen
 
 import (
+	"cmp"
+	"context"
+	"fmt"
+	"image"
 	"io"
+	"slices"
+)
+
+const (
+	defaultWidth  = 1024
+	defaultHeight = 512
+
+	defaultRelativeMinScale = 0.3
+	defaultRelativeMaxScale = 1.0
 )
 
 type Config struct {
+	// Version identifies the revision of the config, it is embedded into generated icons to reproduce them later.
+	Version       string           `toml:"version"`
+	Width         int              `toml:"width"`
+	Height        int              `toml:"height"`
+	Animation     AnimationConfig  `toml:"animation"`
 	Events        []EventConfig    `toml:"events"`
 	Cosmetics     []CosmeticConfig `toml:"cosmetics"`
 	PokemonLayers []PokemonConfig  `toml:"pokemon_layers"`
+	Preview       PreviewConfig    `toml:"preview"`
+}
+
+// PreviewConfig configures the placeholder Pokémon used to preview events and cosmetics without fetching sprites.
+type PreviewConfig struct {
+	// Image is the asset path of the image drawn in place of the Pokémon.
+	// Defaults to "icons/pokeball.png".
+	Image string `toml:"image"`
+	// Pokemon is the number of placeholder Pokémon.
+	// Defaults to 1.
+	Pokemon int `toml:"pokemon"`
+	// Types are the types of the placeholder Pokémon used by type themed layers.
+	// Defaults to "normal".
+	Types []string `toml:"types"`
+	// Event is the event cosmetics are previewed on.
+	// Defaults to the first event.
+	Event string `toml:"event"`
 }
 
 type EventConfig struct {
-	Name   string  `toml:"name"`
-	Layers []Layer `toml:"layers"`
+	Name string `toml:"name"`
+	// Category groups related events, e.g. "Spotlight Hour", and is shown next to the name in the event autocomplete.
+	Category string `toml:"category"`
+	// Tags are additional search terms for the event autocomplete.
+	Tags         []string           `toml:"tags"`
+	Layers       []Layer            `toml:"layers"`
+	Animation    AnimationConfig    `toml:"animation"`
+	RelativeSize RelativeSizeConfig `toml:"relative_size"`
+}
+
+// RelativeSizeConfig scales Pokémon by their in-game height compared to the tallest Pokémon of the icon.
+type RelativeSizeConfig struct {
+	Enabled bool `toml:"enabled"`
+	// MinScale is the smallest scale a Pokémon is drawn at.
+	// Defaults to 0.3.
+	MinScale float64 `toml:"min_scale"`
+	// MaxScale is the biggest scale a Pokémon is drawn at.
+	// Defaults to 1.0.
+	MaxScale float64 `toml:"max_scale"`
+}
+
+// scale returns the scale of a Pokémon with the given height next to a Pokémon with the tallest height.
+func (c RelativeSizeConfig) scale(height int, tallest int) float64 {
+	if !c.Enabled || height <= 0 || tallest <= 0 {
+		return 1
+	}
+	minScale := cmp.Or(c.MinScale, defaultRelativeMinScale)
+	maxScale := cmp.Or(c.MaxScale, defaultRelativeMaxScale)
+	return min(max(float64(height)/float64(tallest), minScale), maxScale)
 }
 
 type CosmeticConfig struct {
-	Name   string  `toml:"name"`
-	Layers []Layer `toml:"layers"`
+	Name string `toml:"name"`
+	// Slot is the part of the icon the cosmetic occupies, e.g. "top-left". Cosmetics in the same slot can't be combined.
+	Slot string `toml:"slot"`
+	// Conflicts are the names of other cosmetics this cosmetic can't be combined with.
+	Conflicts []string `toml:"conflicts"`
+	Layers    []Layer  `toml:"layers"`
+}
+
+// ConflictsWith returns whether the cosmetics share a slot or one of them lists the other as conflict.
+func (c CosmeticConfig) ConflictsWith(other CosmeticConfig) bool {
+	if c.Slot != "" && c.Slot == other.Slot {
+		return true
+	}
+	return slices.Contains(c.Conflicts, other.Name) || slices.Contains(other.Conflicts, c.Name)
+}
+
+// Event returns the event with the given name.
+func (c Config) Event(name string) (EventConfig, bool) {
+	i := slices.IndexFunc(c.Events, func(event EventConfig) bool {
+		return event.Name == name
+	})
+	if i == -1 {
+		return EventConfig{}, false
+	}
+	return c.Events[i], true
+}
+
+// Cosmetic returns the cosmetic with the given name.
+func (c Config) Cosmetic(name string) (CosmeticConfig, bool) {
+	i := slices.IndexFunc(c.Cosmetics, func(cosmetic CosmeticConfig) bool {
+		return cosmetic.Name == name
+	})
+	if i == -1 {
+		return CosmeticConfig{}, false
+	}
+	return c.Cosmetics[i], true
+}
+
+// CheckCosmetics returns an error if a cosmetic doesn't exist, is used twice or conflicts with another cosmetic.
+func (c Config) CheckCosmetics(cosmetics []string) error {
+	configs := make([]CosmeticConfig, 0, len(cosmetics))
+	for _, name := range cosmetics {
+		cosmetic, ok := c.Cosmetic(name)
+		if !ok {
+			return fmt.Errorf("cosmetic %q not found", name)
+		}
+		for _, other := range configs {
+			if other.Name == cosmetic.Name {
+				return fmt.Errorf("cosmetic %q is used more than once", name)
+			}
+			if cosmetic.ConflictsWith(other) {
+				return fmt.Errorf("cosmetics %q and %q can't be combined", other.Name, cosmetic.Name)
+			}
+		}
+		configs = append(configs, cosmetic)
+	}
+	return nil
 }
 
 type PokemonConfig struct {
@@ -45,6 +162,42 @@ func (l LayerID) Order() int {
 	}
 }
 
+type Fill string
+
+const (
+	FillNone           Fill = ""
+	FillSolid          Fill = "solid"
+	FillLinearGradient Fill = "linear-gradient"
+	FillRadialGradient Fill = "radial-gradient"
+	FillPattern        Fill = "pattern"
+)
+
+type Pattern string
+
+const (
+	PatternStripes      Pattern = "stripes"
+	PatternDots         Pattern = "dots"
+	PatternCheckerboard Pattern = "checkerboard"
+)
+
+type Normalize string
+
+const (
+	NormalizeNone Normalize = ""
+	// NormalizeHeight scales the image so its visible pixels are as tall as the untrimmed image would be, even if the
+	// layer is scaled by width or not at all.
+	NormalizeHeight Normalize = "height"
+	// NormalizeArea scales the image so all images cover about the same visible area.
+	NormalizeArea Normalize = "area"
+)
+
+type Anchor string
+
+const (
+	AnchorBounds       Anchor = ""
+	AnchorCenterOfMass Anchor = "center-of-mass"
+)
+
 type Position string
 
 const (
@@ -87,9 +240,63 @@ type Layer struct {
 	FlipY bool `toml:"flip_y"`
 	// Rotate is the rotation of the overlay image in degrees.
 	Rotate float64 `toml:"rotate"`
+	// Fill renders the layer procedurally instead of loading Image.
+	Fill Fill `toml:"fill"`
+	// Colors are the colors of the fill as hex strings (#rgb, #rgba, #rrggbb or #rrggbbaa).
+	// Use "types" to insert the type colors of the featured Pokémon, "dominant" for the dominant color of their sprites
+	// "palette" for the palette extracted from their sprites or "palette:N" for the Nth color of that palette.
+	Colors []string `toml:"colors"`
+	// Angle is the direction of a linear gradient or stripe pattern in degrees.
+	Angle float64 `toml:"angle"`
+	// Pattern is the pattern to draw when Fill is FillPattern.
+	Pattern Pattern `toml:"pattern"`
+	// PatternSize is the size of a pattern cell relative to the background image height.
+	// Defaults to 0.1.
+	PatternSize float64 `toml:"pattern_size"`
+	// Tint is the color the overlay image is tinted with. Accepts the same values as a single entry of Colors.
+	Tint string `toml:"tint"`
+	// TintStrength is how strong the tint is applied from 0.0 to 1.0.
+	// Defaults to 0.5.
+	TintStrength float64 `toml:"tint_strength"`
+	// Glow is the color of a glow drawn around the overlay image. Accepts the same values as a single entry of Colors.
+	Glow string `toml:"glow"`
+	// GlowSize is the radius of the glow relative to the overlay image size.
+	// Defaults to 0.05.
+	GlowSize float64 `toml:"glow_size"`
+	// Keyframes animate the layer when an animated format is generated.
+	Keyframes []Keyframe `toml:"keyframes"`
+	// Trim removes transparent borders of the overlay image before it is scaled.
+	Trim bool `toml:"trim"`
+	// Normalize scales the overlay image by its visible pixels. Implies Trim.
+	Normalize Normalize `toml:"normalize"`
+	// Anchor is the point of the overlay image which is placed at Position.
+	Anchor Anchor `toml:"anchor"`
 }
 
+// Pokemon is a Pokémon drawn on a pokemon layer.
+type Pokemon struct {
+	// Image is the sprite of the Pokémon.
+	Image io.ReadCloser
+	// Types are the type names of the Pokémon in slot order, e.g. "fire".
+	Types []string
+	// Height is the in-game height of the Pokémon in decimetres. 0 if unknown.
+	Height int
+}
+
+// PokemonFunc resolves a Pokémon by name or ID.
+type PokemonFunc func(ctx context.Context, p string) (*Pokemon, error)
+
 type imageLayer struct {
-	Image io.Reader
+	Image image.Image
+	// Frames are all frames of an animated sprite, Image is the first frame.
+	Frames []image.Image
+	// Delays are the delays of Frames in 1/100s.
+	Delays []int
+	// CenterOffset is the distance of the anchor from the center of Image.
+	CenterOffset image.Point
+	// Box is the size offsets are relative to if it differs from the size of Image.
+	Box image.Point
+	// SizeScale is the relative size of a Pokémon compared to the tallest Pokémon of the icon.
+	SizeScale float64
 	Layer
 }
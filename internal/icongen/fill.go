@@ -0,0 +1,251 @@
+package icongen
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	colorTypes    = "types"
+	colorDominant = "dominant"
+	colorPalette  = "palette"
+)
+
+// theme holds the colors derived from the featured Pokémon.
+type theme struct {
+	types   []string
+	palette []color.NRGBA
+}
+
+// typeColors are the colors of the Pokémon types as used in the games.
+var typeColors = map[string]color.NRGBA{
+	"normal":   {R: 0xA8, G: 0xA7, B: 0x7A, A: 0xFF},
+	"fire":     {R: 0xEE, G: 0x81, B: 0x30, A: 0xFF},
+	"water":    {R: 0x63, G: 0x90, B: 0xF0, A: 0xFF},
+	"electric": {R: 0xF7, G: 0xD0, B: 0x2C, A: 0xFF},
+	"grass":    {R: 0x7A, G: 0xC7, B: 0x4C, A: 0xFF},
+	"ice":      {R: 0x96, G: 0xD9, B: 0xD6, A: 0xFF},
+	"fighting": {R: 0xC2, G: 0x2E, B: 0x28, A: 0xFF},
+	"poison":   {R: 0xA3, G: 0x3E, B: 0xA1, A: 0xFF},
+	"ground":   {R: 0xE2, G: 0xBF, B: 0x65, A: 0xFF},
+	"flying":   {R: 0xA9, G: 0x8F, B: 0xF3, A: 0xFF},
+	"psychic":  {R: 0xF9, G: 0x55, B: 0x87, A: 0xFF},
+	"bug":      {R: 0xA6, G: 0xB9, B: 0x1A, A: 0xFF},
+	"rock":     {R: 0xB6, G: 0xA1, B: 0x36, A: 0xFF},
+	"ghost":    {R: 0x73, G: 0x57, B: 0x97, A: 0xFF},
+	"dragon":   {R: 0x6F, G: 0x35, B: 0xFC, A: 0xFF},
+	"dark":     {R: 0x70, G: 0x57, B: 0x46, A: 0xFF},
+	"steel":    {R: 0xB7, G: 0xB7, B: 0xCE, A: 0xFF},
+	"fairy":    {R: 0xD6, G: 0x85, B: 0xAD, A: 0xFF},
+}
+
+func parseColor(s string) (color.NRGBA, error) {
+	hex := strings.TrimPrefix(s, "#")
+	switch len(hex) {
+	case 3, 4:
+		expanded := make([]byte, 0, 8)
+		for i := range len(hex) {
+			expanded = append(expanded, hex[i], hex[i])
+		}
+		hex = string(expanded)
+		if len(hex) == 6 {
+			hex += "ff"
+		}
+	case 6:
+		hex += "ff"
+	case 8:
+	default:
+		return color.NRGBA{}, fmt.Errorf("invalid color %q", s)
+	}
+
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return color.NRGBA{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	return color.NRGBA{
+		R: uint8(v >> 24),
+		G: uint8(v >> 16),
+		B: uint8(v >> 8),
+		A: uint8(v),
+	}, nil
+}
+
+// resolveColors parses the colors of a layer and replaces the theme color names with the colors of the featured Pokémon.
+func resolveColors(colors []string, th theme) ([]color.NRGBA, error) {
+	var resolved []color.NRGBA
+	for _, c := range colors {
+		expanded, err := expandColor(c, th)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, expanded...)
+	}
+	if len(resolved) == 0 {
+		return nil, fmt.Errorf("no colors defined")
+	}
+	return resolved, nil
+}
+
+// resolveColor parses a single color and uses the first color if a theme color name expands to multiple colors.
+func resolveColor(c string, th theme) (color.NRGBA, error) {
+	colors, err := expandColor(c, th)
+	if err != nil {
+		return color.NRGBA{}, err
+	}
+	return colors[0], nil
+}
+
+func expandColor(c string, th theme) ([]color.NRGBA, error) {
+	fallback := []color.NRGBA{typeColors["normal"]}
+	switch {
+	case c == colorTypes:
+		if len(th.types) == 0 {
+			return fallback, nil
+		}
+		colors := make([]color.NRGBA, 0, len(th.types))
+		for _, t := range th.types {
+			tc, ok := typeColors[t]
+			if !ok {
+				return nil, fmt.Errorf("unknown type %q", t)
+			}
+			colors = append(colors, tc)
+		}
+		return colors, nil
+	case c == colorDominant:
+		if len(th.palette) == 0 {
+			return fallback, nil
+		}
+		return th.palette[:1], nil
+	case c == colorPalette:
+		if len(th.palette) == 0 {
+			return fallback, nil
+		}
+		return th.palette, nil
+	case strings.HasPrefix(c, colorPalette+":"):
+		i, err := strconv.Atoi(strings.TrimPrefix(c, colorPalette+":"))
+		if err != nil || i < 1 {
+			return nil, fmt.Errorf("invalid palette color %q", c)
+		}
+		if len(th.palette) == 0 {
+			return fallback, nil
+		}
+		return []color.NRGBA{th.palette[min(i, len(th.palette))-1]}, nil
+	}
+
+	nc, err := parseColor(c)
+	if err != nil {
+		return nil, err
+	}
+	return []color.NRGBA{nc}, nil
+}
+
+func renderFill(bounds image.Rectangle, layer Layer, colors []color.NRGBA) (image.Image, error) {
+	img := image.NewNRGBA(bounds)
+	switch layer.Fill {
+	case FillSolid:
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				img.SetNRGBA(x, y, colors[0])
+			}
+		}
+	case FillLinearGradient:
+		colors = gradientStops(colors)
+		angle := layer.Angle * (math.Pi / 180.0)
+		dx, dy := math.Cos(angle), math.Sin(angle)
+		// project the corners onto the gradient direction to find the gradient length
+		w, h := float64(bounds.Dx()), float64(bounds.Dy())
+		length := math.Abs(w*dx) + math.Abs(h*dy)
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				t := ((float64(x)-w/2)*dx+(float64(y)-h/2)*dy)/length + 0.5
+				img.SetNRGBA(bounds.Min.X+x, bounds.Min.Y+y, gradientAt(colors, t))
+			}
+		}
+	case FillRadialGradient:
+		colors = gradientStops(colors)
+		w, h := float64(bounds.Dx()), float64(bounds.Dy())
+		radius := math.Hypot(w/2, h/2)
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				t := math.Hypot(float64(x)-w/2, float64(y)-h/2) / radius
+				img.SetNRGBA(bounds.Min.X+x, bounds.Min.Y+y, gradientAt(colors, t))
+			}
+		}
+	case FillPattern:
+		switch layer.Pattern {
+		case PatternStripes, PatternDots, PatternCheckerboard:
+		default:
+			return nil, fmt.Errorf("invalid layer pattern: %s", layer.Pattern)
+		}
+		size := layer.PatternSize
+		if size == 0 {
+			size = 0.1
+		}
+		cell := max(float64(bounds.Dy())*size, 1)
+		angle := layer.Angle * (math.Pi / 180.0)
+		var background color.NRGBA
+		if len(colors) > 1 {
+			background = colors[1]
+		}
+		for y := 0; y < bounds.Dy(); y++ {
+			for x := 0; x < bounds.Dx(); x++ {
+				c := background
+				if patternAt(layer.Pattern, float64(x), float64(y), cell, angle) {
+					c = colors[0]
+				}
+				img.SetNRGBA(bounds.Min.X+x, bounds.Min.Y+y, c)
+			}
+		}
+	default:
+		return nil, fmt.Errorf("invalid layer fill: %s", layer.Fill)
+	}
+	return img, nil
+}
+
+// gradientStops makes sure a gradient has at least two colors by fading a single color into a darker shade of itself.
+func gradientStops(colors []color.NRGBA) []color.NRGBA {
+	if len(colors) > 1 {
+		return colors
+	}
+	c := colors[0]
+	return []color.NRGBA{c, {R: c.R / 2, G: c.G / 2, B: c.B / 2, A: c.A}}
+}
+
+func gradientAt(colors []color.NRGBA, t float64) color.NRGBA {
+	t = min(max(t, 0), 1)
+	pos := t * float64(len(colors)-1)
+	i := min(int(pos), len(colors)-2)
+	return lerpColor(colors[i], colors[i+1], pos-float64(i))
+}
+
+func lerpColor(a color.NRGBA, b color.NRGBA, t float64) color.NRGBA {
+	lerp := func(a uint8, b uint8) uint8 {
+		return uint8(math.Round(float64(a) + (float64(b)-float64(a))*t))
+	}
+	return color.NRGBA{
+		R: lerp(a.R, b.R),
+		G: lerp(a.G, b.G),
+		B: lerp(a.B, b.B),
+		A: lerp(a.A, b.A),
+	}
+}
+
+func patternAt(pattern Pattern, x float64, y float64, cell float64, angle float64) bool {
+	switch pattern {
+	case PatternStripes:
+		u := x*math.Cos(angle) + y*math.Sin(angle)
+		return int(math.Floor(u/cell))%2 == 0
+	case PatternDots:
+		cx := math.Mod(x, cell) - cell/2
+		cy := math.Mod(y, cell) - cell/2
+		return math.Hypot(cx, cy) <= cell/4
+	case PatternCheckerboard:
+		return (int(x/cell)+int(y/cell))%2 == 0
+	default:
+		return false
+	}
+}
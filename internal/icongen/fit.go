@@ -0,0 +1,80 @@
+package icongen
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+
+	"golang.org/x/image/draw"
+)
+
+// minFitSize is the smallest width an icon is scaled down to to fit into Options.MaxBytes.
+const minFitSize = 32
+
+// Size limits of Discord for icons uploaded as emojis and stickers.
+const (
+	EmojiSize     = 128
+	EmojiMaxBytes = 256 * 1024
+
+	StickerSize     = 320
+	StickerMaxBytes = 512 * 1024
+)
+
+// fit encodes the frames with the size or width of the options. If the icon is bigger than Options.MaxBytes, it is encoded with
+// the best compression and then scaled down by a quarter until it fits.
+func fit(frames []*image.RGBA, fps int, metadata Metadata, opts Options) ([]byte, error) {
+	width := frames[0].Bounds().Dx()
+	if opts.Size > 0 {
+		frames = cropSquare(frames)
+		width = opts.Size
+	} else if opts.Width > 0 {
+		width = min(width, opts.Width)
+	}
+
+	compression := png.DefaultCompression
+	if opts.MaxBytes > 0 {
+		compression = png.BestCompression
+	}
+	for {
+		data, err := encode(opts.Format, resizeFrames(frames, width), fps, metadata, compression)
+		if err != nil {
+			return nil, err
+		}
+		if opts.MaxBytes <= 0 || len(data) <= opts.MaxBytes {
+			return data, nil
+		}
+		if width = width * 3 / 4; width < minFitSize {
+			return nil, fmt.Errorf("icon is bigger than %d bytes", opts.MaxBytes)
+		}
+	}
+}
+
+// cropSquare crops the biggest square from the center of the frames.
+func cropSquare(frames []*image.RGBA) []*image.RGBA {
+	bounds := frames[0].Bounds()
+	size := min(bounds.Dx(), bounds.Dy())
+	offset := bounds.Min.Add(image.Pt((bounds.Dx()-size)/2, (bounds.Dy()-size)/2))
+
+	cropped := make([]*image.RGBA, len(frames))
+	for i, frame := range frames {
+		cropped[i] = image.NewRGBA(image.Rect(0, 0, size, size))
+		draw.Draw(cropped[i], cropped[i].Bounds(), frame, offset, draw.Src)
+	}
+	return cropped
+}
+
+// resizeFrames scales the frames to the width keeping their aspect ratio.
+func resizeFrames(frames []*image.RGBA, width int) []*image.RGBA {
+	bounds := frames[0].Bounds()
+	if bounds.Dx() == width {
+		return frames
+	}
+	height := max(bounds.Dy()*width/bounds.Dx(), 1)
+
+	resized := make([]*image.RGBA, len(frames))
+	for i, frame := range frames {
+		resized[i] = image.NewRGBA(image.Rect(0, 0, width, height))
+		draw.CatmullRom.Scale(resized[i], resized[i].Bounds(), frame, frame.Bounds(), draw.Src, nil)
+	}
+	return resized
+}
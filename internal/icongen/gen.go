@@ -2,9 +2,12 @@ package icongen
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	"fmt"
 	"image"
+	"image/color"
+	_ "image/gif"
 	_ "image/jpeg"
 	"image/png"
 	"io"
@@ -15,17 +18,35 @@ import (
 	"golang.org/x/image/draw"
 )
 
-func Generate(ctx context.Context, assets fs.FS, cfg Config, pokemonImage func(ctx context.Context, p string) (io.ReadCloser, error), event string, pokemon []string, cosmetics []string) (io.Reader, error) {
-	var eventCfg EventConfig
-	for _, e := range cfg.Events {
-		if e.Name == event {
-			eventCfg = e
-			break
-		}
-	}
-	if eventCfg.Name == "" {
+type Options struct {
+	// Format is the format of the generated icon.
+	// Defaults to FormatPNG.
+	Format Format
+	// Metadata are additional parameters embedded into the icon, e.g. the sprite style. See Metadata.
+	Metadata map[string]string
+	// Version is the version of the program generating the icon, it is embedded into the icon.
+	Version string
+	// Size crops a square from the center of the icon and scales it to Size x Size pixels, e.g. for emojis.
+	// Defaults to the full icon.
+	Size int
+	// Width scales the icon down to the width keeping its aspect ratio. Ignored if Size is set.
+	// Defaults to the full width.
+	Width int
+	// Layers are drawn on top of the cosmetics, e.g. a branding logo. Their images are loaded from the assets.
+	Layers []Layer
+	// MaxBytes is the maximum size of the encoded icon. Bigger icons are compressed harder and scaled down until they fit.
+	// Defaults to no limit.
+	MaxBytes int
+}
+
+func Generate(ctx context.Context, assets fs.FS, cfg Config, pokemonFunc PokemonFunc, event string, pokemon []string, cosmetics []string, opts Options) (io.Reader, error) {
+	eventCfg, ok := cfg.Event(event)
+	if !ok {
 		return nil, fmt.Errorf("event %q not found", event)
 	}
+	if err := cfg.CheckCosmetics(cosmetics); err != nil {
+		return nil, err
+	}
 
 	layers := eventCfg.Layers
 
@@ -46,89 +67,274 @@ func Generate(ctx context.Context, assets fs.FS, cfg Config, pokemonImage func(c
 		index = len(layers)
 	}
 
+	var th theme
+	var sprites []image.Image
 	pokemonLayers := make([]imageLayer, 0, len(pokemon))
+	heights := make([]int, 0, len(pokemon))
+	if len(pokemon) > len(cfg.PokemonLayers) {
+		return nil, fmt.Errorf("too many pokemon: at most %d are supported", len(cfg.PokemonLayers))
+	}
 	if len(pokemon) > 0 {
 		pLayers := cfg.PokemonLayers[len(pokemon)-1].Layers
 		for i, p := range pokemon {
-			img, err := pokemonImage(ctx, p)
+			pkm, err := pokemonFunc(ctx, p)
 			if err != nil {
 				return nil, fmt.Errorf("failed to get pokemon image: %w", err)
 			}
-			defer img.Close()
-			pLayer := pLayers[i]
-			pLayer.Image = p
-			pokemonLayers = append(pokemonLayers, imageLayer{
-				Image: img,
-				Layer: pLayer,
-			})
+			imgLayer, err := decodeSprite(pkm.Image, p)
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range pkm.Types {
+				if !slices.Contains(th.types, t) {
+					th.types = append(th.types, t)
+				}
+			}
+			sprites = append(sprites, imgLayer.Image)
+			imgLayer.Layer = pLayers[i]
+			imgLayer.Layer.Image = p
+			pokemonLayers = append(pokemonLayers, imgLayer)
+			heights = append(heights, pkm.Height)
 		}
 	}
+	tallest := slices.Max(append(heights, 0))
+	for i, height := range heights {
+		pokemonLayers[i].SizeScale = eventCfg.RelativeSize.scale(height, tallest)
+	}
+	th.palette = extractPalette(sprites, paletteSize)
 
 	imgLayers := make([]imageLayer, 0, len(layers))
 	for _, layer := range layers {
-		img, err := assets.Open(layer.Image)
+		imgLayer, err := openLayer(assets, layer)
 		if err != nil {
 			return nil, fmt.Errorf("failed to open layer image: %w", err)
 		}
-		defer img.Close()
-
-		imgLayers = append(imgLayers, imageLayer{
-			Image: img,
-			Layer: layer,
-		})
+		imgLayers = append(imgLayers, imgLayer)
 	}
 
 	imgLayers = slices.Insert(imgLayers, index, pokemonLayers...)
 
 	for _, c := range cosmetics {
-		i := slices.IndexFunc(cfg.Cosmetics, func(config CosmeticConfig) bool {
-			return config.Name == c
-		})
-		if i == -1 {
-			return nil, fmt.Errorf("cosmetic %q not found", c)
-		}
-
-		for _, layer := range cfg.Cosmetics[i].Layers {
-			img, err := assets.Open(layer.Image)
+		cosmetic, _ := cfg.Cosmetic(c)
+		for _, layer := range cosmetic.Layers {
+			imgLayer, err := openLayer(assets, layer)
 			if err != nil {
 				return nil, fmt.Errorf("failed to open cosmetic image: %w", err)
 			}
-			defer img.Close()
+			imgLayers = append(imgLayers, imgLayer)
+		}
+	}
 
-			imgLayers = append(imgLayers, imageLayer{
-				Image: img,
-				Layer: layer,
-			})
+	for _, layer := range opts.Layers {
+		imgLayer, err := openLayer(assets, layer)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open layer image: %w", err)
 		}
+		imgLayers = append(imgLayers, imgLayer)
 	}
 
-	var newImage *image.RGBA
+	if len(imgLayers) == 0 {
+		return nil, fmt.Errorf("event %q has no layers", event)
+	}
+
+	bounds := image.Rect(0, 0, cmp.Or(cfg.Width, defaultWidth), cmp.Or(cfg.Height, defaultHeight))
+	if imgLayers[0].Image != nil {
+		bounds = imgLayers[0].Image.Bounds()
+	}
 	for i, layer := range imgLayers {
-		img, _, err := image.Decode(layer.Image)
+		switch layer.Normalize {
+		case NormalizeNone, NormalizeHeight, NormalizeArea:
+		default:
+			return nil, fmt.Errorf("invalid layer normalize: %s", layer.Normalize)
+		}
+		if layer.Image != nil && (layer.Trim || layer.Normalize != NormalizeNone) {
+			// keep offsets relative to the untrimmed size so layouts stay the same for every image
+			layer.Box = resizedSize(bounds, layer.Image.Bounds(), layer.ScaleX, layer.ScaleY)
+			layer = trimLayer(layer)
+		}
+		scale := cmp.Or(layer.SizeScale, 1)
+		if layer.Image != nil {
+			switch layer.Normalize {
+			case NormalizeHeight:
+				scale *= heightScale(layer.Box, resizedSize(bounds, layer.Image.Bounds(), layer.ScaleX, layer.ScaleY))
+			case NormalizeArea:
+				scale *= areaScale(layer.Image)
+			}
+		}
+
+		img, err := renderLayer(layer, bounds, th)
 		if err != nil {
-			return nil, fmt.Errorf("failed to decode image %q: %w", layer.Layer.Image, err)
+			return nil, err
 		}
-		if i == 0 {
-			newImage = image.NewRGBA(img.Bounds())
+		if img, err = transformLayer(bounds, img, layer.Layer, th); err != nil {
+			return nil, err
 		}
+		layer.Image = scaleLayer(img, scale)
 
-		if err = applyOverlay(newImage, img, layer); err != nil {
-			return nil, fmt.Errorf("failed to layer template: %w", err)
+		for j, frame := range layer.Frames {
+			if frame, err = transformLayer(bounds, frame, layer.Layer, th); err != nil {
+				return nil, err
+			}
+			layer.Frames[j] = scaleLayer(frame, scale)
+		}
+
+		if layer.Anchor == AnchorCenterOfMass {
+			layer.CenterOffset = centerOffset(layer.Image)
+		}
+		if layer.SizeScale > 0 && layer.SizeScale != 1 {
+			// smaller Pokémon stand on the same ground as the tallest one
+			height := float64(layer.Image.Bounds().Dy()) / layer.SizeScale
+			layer.CenterOffset.Y -= int(height * (1 - layer.SizeScale) / 2)
+		}
+		imgLayers[i] = layer
+	}
+
+	metadata := Metadata{
+		Event:     event,
+		Pokemon:   pokemon,
+		Cosmetics: cosmetics,
+		Format:    opts.Format,
+		Extra:     opts.Metadata,
+
+		ConfigVersion:    cfg.Version,
+		GeneratorVersion: opts.Version,
+	}
+
+	var frames []*image.RGBA
+	var fps int
+	if !opts.Format.Animated() {
+		newImage, err := renderFrame(bounds, imgLayers, th, 0, 0)
+		if err != nil {
+			return nil, err
+		}
+		frames = append(frames, newImage)
+	} else {
+		frameCount := cmp.Or(eventCfg.Animation.Frames, cfg.Animation.Frames, defaultFrames)
+		fps = cmp.Or(eventCfg.Animation.FPS, cfg.Animation.FPS, defaultFPS)
+		// make sure animated sprites play at least one full loop
+		for _, layer := range imgLayers {
+			if loop := loopDuration(layer.Delays); loop > 0 {
+				frameCount = max(frameCount, (loop*fps+99)/100)
+			}
+		}
+		frames = make([]*image.RGBA, 0, frameCount)
+		for i := range frameCount {
+			frame, err := renderFrame(bounds, imgLayers, th, float64(i)/float64(frameCount), i*100/fps)
+			if err != nil {
+				return nil, err
+			}
+			frames = append(frames, frame)
 		}
 	}
 
+	data, err := fit(frames, fps, metadata, opts)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.NewReader(data), nil
+}
+
+// encode encodes the frames in the format and embeds the metadata into PNG and APNG icons.
+func encode(format Format, frames []*image.RGBA, fps int, metadata Metadata, compression png.CompressionLevel) ([]byte, error) {
 	buf := new(bytes.Buffer)
-	if err := png.Encode(buf, newImage); err != nil {
-		return nil, fmt.Errorf("failed to encode image: %w", err)
+	switch format {
+	case FormatGIF:
+		if err := encodeGIF(buf, frames, fps); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case FormatAPNG:
+		if err := encodeAPNG(buf, frames, fps); err != nil {
+			return nil, err
+		}
+	default:
+		encoder := png.Encoder{CompressionLevel: compression}
+		if err := encoder.Encode(buf, frames[0]); err != nil {
+			return nil, fmt.Errorf("failed to encode image: %w", err)
+		}
 	}
-	return bytes.NewReader(buf.Bytes()), nil
+	return embedMetadata(buf.Bytes(), metadata)
 }
 
-func applyOverlay(baseImg *image.RGBA, img image.Image, layer imageLayer) error {
-	img = resizeLayer(baseImg, img, layer.ScaleX, layer.ScaleY)
+// renderFrame draws all layers at time t of the animation from 0.0 to 1.0.
+// Animated sprites are drawn at elapsed in 1/100s.
+func renderFrame(bounds image.Rectangle, imgLayers []imageLayer, th theme, t float64, elapsed int) (*image.RGBA, error) {
+	newImage := image.NewRGBA(bounds)
+	for _, layer := range imgLayers {
+		img := layer.Image
+		if len(layer.Frames) > 0 {
+			img = layer.Frames[frameAt(layer.Delays, elapsed)]
+		}
+		if err := applyOverlay(newImage, img, layer, th, stateAt(layer.Keyframes, t)); err != nil {
+			return nil, fmt.Errorf("failed to layer template: %w", err)
+		}
+	}
+	return newImage, nil
+}
+
+func decodeImage(r io.ReadCloser, name string) (image.Image, error) {
+	defer r.Close()
+	img, _, err := image.Decode(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image %q: %w", name, err)
+	}
+	return img, nil
+}
+
+// openLayer decodes the image of a layer. Layers with a fill are rendered later once the icon size is known.
+func openLayer(assets fs.FS, layer Layer) (imageLayer, error) {
+	if layer.Fill != FillNone {
+		return imageLayer{
+			Layer: layer,
+		}, nil
+	}
+
+	r, err := assets.Open(layer.Image)
+	if err != nil {
+		return imageLayer{}, err
+	}
+	img, err := decodeImage(r, layer.Image)
+	if err != nil {
+		return imageLayer{}, err
+	}
+	return imageLayer{
+		Image: img,
+		Layer: layer,
+	}, nil
+}
+
+func renderLayer(layer imageLayer, bounds image.Rectangle, th theme) (image.Image, error) {
+	if layer.Fill == FillNone {
+		return layer.Image, nil
+	}
+
+	colors, err := resolveColors(layer.Colors, th)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve layer colors: %w", err)
+	}
+	img, err := renderFill(bounds, layer.Layer, colors)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render layer fill: %w", err)
+	}
+	return img, nil
+}
+
+// transformLayer applies all transformations of a layer which don't change during an animation.
+func transformLayer(baseBounds image.Rectangle, img image.Image, layer Layer, th theme) (image.Image, error) {
+	img = resizeLayer(baseBounds, img, layer.ScaleX, layer.ScaleY)
 	img = flipLayer(img, layer.FlipX, layer.FlipY)
 	img = rotateLayer(img, layer.Rotate)
+	if layer.Tint != "" {
+		tint, err := resolveColor(layer.Tint, th)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve tint color: %w", err)
+		}
+		img = tintLayer(img, tint, cmp.Or(layer.TintStrength, 0.5))
+	}
+	return img, nil
+}
 
+func applyOverlay(baseImg *image.RGBA, img image.Image, layer imageLayer, th theme, state frameState) error {
 	bounds := img.Bounds()
 	baseBounds := baseImg.Bounds()
 	var (
@@ -137,7 +343,7 @@ func applyOverlay(baseImg *image.RGBA, img image.Image, layer imageLayer) error
 	)
 	switch layer.Position {
 	case PositionTop:
-		offsetX = baseBounds.Dx() / 2
+		offsetX = (baseBounds.Dx() - bounds.Dx()) / 2
 		offsetY = 0
 	case PositionTopLeft:
 		offsetX = 0
@@ -146,13 +352,13 @@ func applyOverlay(baseImg *image.RGBA, img image.Image, layer imageLayer) error
 		offsetX = baseBounds.Dx() - bounds.Dx()
 		offsetY = 0
 	case PositionBottom:
-		offsetX = baseBounds.Dx() / 2
+		offsetX = (baseBounds.Dx() - bounds.Dx()) / 2
 		offsetY = baseBounds.Dy() - bounds.Dy()
 	case PositionBottomLeft:
 		offsetX = 0
 		offsetY = baseBounds.Dy() - bounds.Dy()
 	case PositionBottomRight:
-		offsetX = baseBounds.Dx()
+		offsetX = baseBounds.Dx() - bounds.Dx()
 		offsetY = baseBounds.Dy() - bounds.Dy()
 	case PositionCenter, PositionEmpty:
 		offsetX = (baseBounds.Dx() - bounds.Dx()) / 2
@@ -166,22 +372,63 @@ func applyOverlay(baseImg *image.RGBA, img image.Image, layer imageLayer) error
 	default:
 		return fmt.Errorf("invalid layer position: %s", layer.Position)
 	}
-	if layer.OffsetX != 0 {
-		offsetX += int(float64(bounds.Dx()) * layer.OffsetX)
+	switch layer.Anchor {
+	case AnchorBounds, AnchorCenterOfMass:
+	default:
+		return fmt.Errorf("invalid layer anchor: %s", layer.Anchor)
+	}
+	offsetX -= layer.CenterOffset.X
+	offsetY -= layer.CenterOffset.Y
+	box := bounds.Size()
+	if layer.Box != (image.Point{}) {
+		box = layer.Box
 	}
-	if layer.OffsetY != 0 {
-		offsetY += int(float64(bounds.Dy()) * layer.OffsetY)
+	if offsetXScale := layer.OffsetX + state.offsetX; offsetXScale != 0 {
+		offsetX += int(float64(box.X) * offsetXScale)
+	}
+	if offsetYScale := layer.OffsetY + state.offsetY; offsetYScale != 0 {
+		offsetY += int(float64(box.Y) * offsetYScale)
 	}
 
-	draw.Draw(baseImg, image.Rect(offsetX, offsetY, offsetX+bounds.Dx(), offsetY+bounds.Dy()), img, image.Point{}, draw.Over)
+	if state.scale != 1 || state.rotate != 0 {
+		// keep the layer centered on its original position while scaling
+		img = scaleLayer(img, state.scale)
+		img = rotateLayer(img, state.rotate)
+		offsetX -= (img.Bounds().Dx() - bounds.Dx()) / 2
+		offsetY -= (img.Bounds().Dy() - bounds.Dy()) / 2
+		bounds = img.Bounds()
+	}
+
+	var mask image.Image
+	if state.opacity < 1 {
+		mask = image.NewUniform(color.Alpha{A: uint8(max(state.opacity, 0) * 0xFF)})
+	}
+
+	if layer.Glow != "" {
+		glow, err := resolveColor(layer.Glow, th)
+		if err != nil {
+			return fmt.Errorf("failed to resolve glow color: %w", err)
+		}
+		radius := int(float64(max(bounds.Dx(), bounds.Dy())) * cmp.Or(layer.GlowSize, 0.05))
+		glowImg := glowLayer(img, glow, radius)
+		draw.DrawMask(baseImg, image.Rect(offsetX-radius, offsetY-radius, offsetX+bounds.Dx()+radius, offsetY+bounds.Dy()+radius), glowImg, image.Point{}, mask, image.Point{}, draw.Over)
+	}
+
+	draw.DrawMask(baseImg, image.Rect(offsetX, offsetY, offsetX+bounds.Dx(), offsetY+bounds.Dy()), img, image.Point{}, mask, image.Point{}, draw.Over)
 
 	return nil
 }
 
-func resizeLayer(baseImg image.Image, img image.Image, scaleX float64, scaleY float64) image.Image {
+func resizeLayer(baseBounds image.Rectangle, img image.Image, scaleX float64, scaleY float64) image.Image {
 	bounds := img.Bounds()
-	baseBounds := baseImg.Bounds()
+	size := resizedSize(baseBounds, bounds, scaleX, scaleY)
+
+	resizedImg := image.NewRGBA(image.Rect(0, 0, size.X, size.Y))
+	draw.BiLinear.Scale(resizedImg, resizedImg.Bounds(), img, bounds, draw.Src, nil)
+	return resizedImg
+}
 
+func resizedSize(baseBounds image.Rectangle, bounds image.Rectangle, scaleX float64, scaleY float64) image.Point {
 	newWidth := bounds.Dx()
 	newHeight := bounds.Dy()
 	if scaleX != 1 && scaleX != 0 {
@@ -193,10 +440,7 @@ func resizeLayer(baseImg image.Image, img image.Image, scaleX float64, scaleY fl
 		// scale the width to keep the aspect ratio
 		newWidth = int(float64(newHeight) * float64(bounds.Dx()) / float64(bounds.Dy()))
 	}
-
-	resizedImg := image.NewRGBA(image.Rect(0, 0, newWidth, newHeight))
-	draw.BiLinear.Scale(resizedImg, resizedImg.Bounds(), img, bounds, draw.Src, nil)
-	return resizedImg
+	return image.Pt(newWidth, newHeight)
 }
 
 func flipLayer(img image.Image, flipX bool, flipY bool) image.Image {
@@ -231,16 +475,37 @@ func flipYLayer(img image.Image) image.Image {
 	return newImg
 }
 
+func scaleLayer(img image.Image, scale float64) image.Image {
+	if scale == 1 {
+		return img
+	}
+	bounds := img.Bounds()
+	newImg := image.NewRGBA(image.Rect(0, 0, max(int(float64(bounds.Dx())*scale), 1), max(int(float64(bounds.Dy())*scale), 1)))
+	draw.BiLinear.Scale(newImg, newImg.Bounds(), img, bounds, draw.Src, nil)
+	return newImg
+}
+
+// rotateLayer rotates the image around its center. Parts rotated outside the original bounds are cut off.
 func rotateLayer(img image.Image, angle float64) image.Image {
+	if angle == 0 {
+		return img
+	}
 	bounds := img.Bounds()
 	newImg := image.NewRGBA(bounds)
 
 	angle = angle * (math.Pi / 180.0) // Convert degrees to radians
+	sin, cos := math.Sin(angle), math.Cos(angle)
+	cx, cy := float64(bounds.Dx())/2, float64(bounds.Dy())/2
 	for y := 0; y < bounds.Dy(); y++ {
 		for x := 0; x < bounds.Dx(); x++ {
-			newX := int(float64(x)*math.Cos(angle) - float64(y)*math.Sin(angle))
-			newY := int(float64(x)*math.Sin(angle) + float64(y)*math.Cos(angle))
-			newImg.Set(newX, newY, img.At(x, y))
+			// map every target pixel back to its source pixel to avoid holes
+			dx, dy := float64(x)+0.5-cx, float64(y)+0.5-cy
+			srcX := int(math.Floor(dx*cos + dy*sin + cx))
+			srcY := int(math.Floor(-dx*sin + dy*cos + cy))
+			if srcX < 0 || srcY < 0 || srcX >= bounds.Dx() || srcY >= bounds.Dy() {
+				continue
+			}
+			newImg.Set(bounds.Min.X+x, bounds.Min.Y+y, img.At(bounds.Min.X+srcX, bounds.Min.Y+srcY))
 		}
 	}
 	return newImg
@@ -1,10 +1,19 @@
 package icongen
 
 import (
+	"bytes"
 	"context"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"image/png"
 	"io"
 	"os"
+	"strings"
 	"testing"
+	"testing/fstest"
 
 	"github.com/topi314/pogo-icons/internal/pokeapi"
 )
@@ -94,7 +103,7 @@ func TestGenerate(t *testing.T) {
 		},
 	}
 
-	var getPokemonImage = func(ctx context.Context, p string) (io.ReadCloser, error) {
+	var getPokemon = func(ctx context.Context, p string) (*Pokemon, error) {
 		pf, err := client.GetPokemonForm(ctx, p)
 		if err != nil {
 			return nil, err
@@ -103,12 +112,15 @@ func TestGenerate(t *testing.T) {
 		if err != nil {
 			return nil, err
 		}
-		return sprite.Body, nil
+		return &Pokemon{
+			Image: sprite.Body,
+			Types: pf.Types,
+		}, nil
 	}
 
 	assets := os.DirFS("../../assets")
 
-	img, err := Generate(t.Context(), assets, cfg, getPokemonImage, event, pokemon, cosmetics)
+	img, err := Generate(t.Context(), assets, cfg, getPokemon, event, pokemon, cosmetics, Options{})
 	if err != nil {
 		t.Fatalf("failed to generate image: %v", err)
 	}
@@ -125,3 +137,467 @@ func TestGenerate(t *testing.T) {
 	}
 	t.Logf("Image generated successfully and saved to output.png")
 }
+
+func TestGenerateFill(t *testing.T) {
+	cfg := Config{
+		Events: []EventConfig{
+			{
+				Name: "test",
+				Layers: []Layer{
+					{
+						ID:     LayerIDBackground,
+						Fill:   FillLinearGradient,
+						Colors: []string{"types"},
+						Angle:  45,
+					},
+					{
+						ID:      LayerIDBackground,
+						Fill:    FillPattern,
+						Pattern: PatternStripes,
+						Colors:  []string{"#fff3"},
+						Angle:   45,
+					},
+				},
+			},
+		},
+	}
+
+	img, err := Generate(t.Context(), os.DirFS("../../assets"), cfg, nil, "test", nil, nil, Options{})
+	if err != nil {
+		t.Fatalf("failed to generate image: %v", err)
+	}
+
+	decoded, err := png.Decode(img)
+	if err != nil {
+		t.Fatalf("failed to decode image: %v", err)
+	}
+	if bounds := decoded.Bounds(); bounds.Dx() != defaultWidth || bounds.Dy() != defaultHeight {
+		t.Fatalf("unexpected image size: %v", bounds)
+	}
+}
+
+func TestGenerateAnimated(t *testing.T) {
+	scale := 1.5
+	cfg := Config{
+		Width:  64,
+		Height: 32,
+		Animation: AnimationConfig{
+			Frames: 4,
+			FPS:    10,
+		},
+		Events: []EventConfig{
+			{
+				Name: "test",
+				Layers: []Layer{
+					{
+						ID:     LayerIDBackground,
+						Fill:   FillSolid,
+						Colors: []string{"#000"},
+					},
+					{
+						ID:      LayerIDBackground,
+						Fill:    FillPattern,
+						Pattern: PatternDots,
+						Colors:  []string{"#fff"},
+						ScaleY:  0.5,
+						Keyframes: []Keyframe{
+							{Time: 0},
+							{Time: 0.5, Scale: &scale, Rotate: 45},
+							{Time: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	img, err := Generate(t.Context(), os.DirFS("../../assets"), cfg, nil, "test", nil, nil, Options{Format: FormatGIF})
+	if err != nil {
+		t.Fatalf("failed to generate image: %v", err)
+	}
+
+	decoded, err := gif.DecodeAll(img)
+	if err != nil {
+		t.Fatalf("failed to decode image: %v", err)
+	}
+	if len(decoded.Image) != 4 {
+		t.Fatalf("unexpected frame count: %d", len(decoded.Image))
+	}
+}
+
+func TestTrimLayer(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 10, 10))
+	draw.Draw(img, image.Rect(2, 3, 6, 8), image.White, image.Point{}, draw.Src)
+
+	layer := trimLayer(imageLayer{Image: img})
+	if size := layer.Image.Bounds().Size(); size != image.Pt(4, 5) {
+		t.Fatalf("unexpected trimmed size: %v", size)
+	}
+	if offset := centerOffset(layer.Image); offset != (image.Point{}) {
+		t.Fatalf("unexpected center offset: %v", offset)
+	}
+}
+
+func TestGenerateNormalizeHeight(t *testing.T) {
+	// a flat sprite with transparent padding, the visible pixels are 20x5
+	sprite := image.NewNRGBA(image.Rect(0, 0, 20, 20))
+	draw.Draw(sprite, image.Rect(0, 0, 20, 5), image.Black, image.Point{}, draw.Src)
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, sprite); err != nil {
+		t.Fatalf("failed to encode sprite: %v", err)
+	}
+	assets := fstest.MapFS{"sprite.png": {Data: buf.Bytes()}}
+
+	cfg := Config{
+		Events: []EventConfig{
+			{
+				Name: "test",
+				Layers: []Layer{
+					{
+						ID:     LayerIDBackground,
+						Fill:   FillSolid,
+						Colors: []string{"#fff"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		trim      bool
+		normalize Normalize
+		// height is the visible height of the 32x32 layer
+		height int
+	}{
+		{name: "trim", trim: true, height: 8},
+		{name: "height", normalize: NormalizeHeight, height: 32},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := Generate(t.Context(), assets, cfg, nil, "test", nil, nil, Options{
+				Width: 256,
+				Layers: []Layer{
+					{
+						Image:     "sprite.png",
+						ScaleX:    0.125,
+						Trim:      tt.trim,
+						Normalize: tt.normalize,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to generate image: %v", err)
+			}
+			decoded, err := png.Decode(img)
+			if err != nil {
+				t.Fatalf("failed to decode image: %v", err)
+			}
+			var height int
+			for y := range decoded.Bounds().Dy() {
+				if r, _, _, _ := decoded.At(128, y).RGBA(); r < 0x8000 {
+					height++
+				}
+			}
+			if height < tt.height-1 || height > tt.height+1 {
+				t.Fatalf("got visible height %d, want %d", height, tt.height)
+			}
+		})
+	}
+}
+
+func TestRelativeSizeScale(t *testing.T) {
+	cfg := RelativeSizeConfig{
+		Enabled:  true,
+		MinScale: 0.4,
+	}
+	for _, tt := range []struct {
+		height int
+		want   float64
+	}{
+		{height: 145, want: 1},
+		{height: 87, want: 0.6},
+		{height: 4, want: 0.4},
+		{height: 0, want: 1},
+	} {
+		if got := cfg.scale(tt.height, 145); got != tt.want {
+			t.Errorf("scale(%d) = %v, want %v", tt.height, got, tt.want)
+		}
+	}
+}
+
+func TestCheckCosmetics(t *testing.T) {
+	cfg := Config{
+		Cosmetics: []CosmeticConfig{
+			{Name: "Badge Left", Slot: "top-left"},
+			{Name: "Badge Right", Slot: "top-right"},
+			{Name: "Shiny", Slot: "top-left"},
+			{Name: "Frame", Conflicts: []string{"Badge Right"}},
+		},
+	}
+	for _, tt := range []struct {
+		cosmetics []string
+		valid     bool
+	}{
+		{cosmetics: nil, valid: true},
+		{cosmetics: []string{"Badge Left", "Badge Right"}, valid: true},
+		{cosmetics: []string{"Shiny", "Frame"}, valid: true},
+		{cosmetics: []string{"Badge Left", "Shiny"}, valid: false},
+		{cosmetics: []string{"Badge Right", "Frame"}, valid: false},
+		{cosmetics: []string{"Frame", "Frame"}, valid: false},
+		{cosmetics: []string{"Unknown"}, valid: false},
+	} {
+		if err := cfg.CheckCosmetics(tt.cosmetics); (err == nil) != tt.valid {
+			t.Errorf("CheckCosmetics(%q) = %v, want valid %t", tt.cosmetics, err, tt.valid)
+		}
+	}
+}
+
+func TestContactSheet(t *testing.T) {
+	var cfg Config
+	for _, event := range [][2]string{{"red", "#f00"}, {"green", "#0f0"}, {"blue", "#00f"}} {
+		cfg.Events = append(cfg.Events, EventConfig{
+			Name: event[0],
+			Layers: []Layer{
+				{
+					ID:     LayerIDBackground,
+					Fill:   FillSolid,
+					Colors: []string{event[1]},
+				},
+			},
+		})
+	}
+
+	img, err := ContactSheet(t.Context(), os.DirFS("../../assets"), cfg, []string{"red", "green", "blue"}, SheetOptions{
+		Columns:        2,
+		ThumbnailWidth: 100,
+	})
+	if err != nil {
+		t.Fatalf("failed to generate contact sheet: %v", err)
+	}
+
+	decoded, err := png.Decode(img)
+	if err != nil {
+		t.Fatalf("failed to decode image: %v", err)
+	}
+	if width := decoded.Bounds().Dx(); width != 2*(100+sheetPadding)+sheetPadding {
+		t.Fatalf("unexpected contact sheet width: %d", width)
+	}
+	if r, g, b, _ := decoded.At(sheetPadding+10, sheetPadding+10).RGBA(); r>>8 != 0xff || g != 0 || b != 0 {
+		t.Fatalf("unexpected color of first thumbnail: %d %d %d", r>>8, g>>8, b>>8)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	cfg := Config{
+		Version: "1",
+		Events: []EventConfig{
+			{
+				Name: "test",
+				Layers: []Layer{
+					{
+						ID:     LayerIDBackground,
+						Fill:   FillSolid,
+						Colors: []string{"#fff"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, format := range []Format{FormatPNG, FormatAPNG} {
+		img, err := Generate(t.Context(), os.DirFS("../../assets"), cfg, nil, "test", nil, nil, Options{
+			Format:   format,
+			Metadata: map[string]string{"style": "home"},
+			Version:  "v1.0.0",
+		})
+		if err != nil {
+			t.Fatalf("failed to generate image: %v", err)
+		}
+		data, err := io.ReadAll(img)
+		if err != nil {
+			t.Fatalf("failed to read image: %v", err)
+		}
+		if _, err = png.Decode(bytes.NewReader(data)); err != nil {
+			t.Fatalf("failed to decode %s image: %v", format, err)
+		}
+
+		metadata, err := ReadMetadata(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to read %s metadata: %v", format, err)
+		}
+		if metadata.Event != "test" || metadata.Format != format || metadata.Extra["style"] != "home" || metadata.ConfigVersion != "1" || metadata.GeneratorVersion != "v1.0.0" {
+			t.Fatalf("unexpected %s metadata: %+v", format, metadata)
+		}
+		if !bytes.Contains(data, []byte("tEXtSoftware\x00pogo-icons v1.0.0")) {
+			t.Fatalf("missing software text chunk in %s image", format)
+		}
+	}
+}
+
+func TestReadMetadataInvalid(t *testing.T) {
+	chunk := func(length uint32, typ string, data string) string {
+		return string(binary.BigEndian.AppendUint32(nil, length)) + typ + data
+	}
+	tests := []struct {
+		name string
+		data string
+	}{
+		{name: "oversized", data: chunk(0xfffffffd, "iTXt", "")},
+		{name: "too large", data: chunk(maxMetadataChunkSize+1, "iTXt", "")},
+		{name: "truncated", data: chunk(64, "iTXt", metadataKeyword+"\x00")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ReadMetadata(strings.NewReader(string(pngSignature) + tt.data)); err == nil {
+				t.Fatal("expected error")
+			}
+		})
+	}
+}
+
+func TestGenerateFit(t *testing.T) {
+	cfg := Config{
+		Events: []EventConfig{
+			{
+				Name: "test",
+				Layers: []Layer{
+					{
+						ID:     LayerIDBackground,
+						Fill:   FillLinearGradient,
+						Colors: []string{"#f00", "#00f"},
+					},
+				},
+			},
+		},
+	}
+
+	for _, format := range []Format{FormatPNG, FormatAPNG, FormatGIF} {
+		img, err := Generate(t.Context(), os.DirFS("../../assets"), cfg, nil, "test", nil, nil, Options{
+			Format:   format,
+			Size:     EmojiSize,
+			MaxBytes: 32 * 1024,
+		})
+		if err != nil {
+			t.Fatalf("failed to generate %s image: %v", format, err)
+		}
+		data, err := io.ReadAll(img)
+		if err != nil {
+			t.Fatalf("failed to read image: %v", err)
+		}
+		if len(data) > 32*1024 {
+			t.Fatalf("%s image is %d bytes, want at most %d", format, len(data), 32*1024)
+		}
+		imgCfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("failed to decode %s image: %v", format, err)
+		}
+		if imgCfg.Width != imgCfg.Height || imgCfg.Width > EmojiSize {
+			t.Fatalf("unexpected %s size %dx%d", format, imgCfg.Width, imgCfg.Height)
+		}
+	}
+}
+
+func TestGenerateOptionsLayers(t *testing.T) {
+	cfg := Config{
+		Events: []EventConfig{
+			{
+				Name: "test",
+				Layers: []Layer{
+					{
+						ID:     LayerIDBackground,
+						Fill:   FillSolid,
+						Colors: []string{"#fff"},
+					},
+				},
+			},
+		},
+	}
+
+	tests := []struct {
+		position Position
+		// area is the part of the 256x128 icon the 32x32 layer has to be drawn in
+		area image.Rectangle
+	}{
+		{position: PositionTopLeft, area: image.Rect(0, 0, 32, 32)},
+		{position: PositionBottomRight, area: image.Rect(224, 96, 256, 128)},
+		{position: PositionTop, area: image.Rect(112, 0, 144, 32)},
+		{position: PositionBottom, area: image.Rect(112, 96, 144, 128)},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.position), func(t *testing.T) {
+			img, err := Generate(t.Context(), os.DirFS("../../assets"), cfg, nil, "test", nil, nil, Options{
+				Width: 256,
+				Layers: []Layer{
+					{
+						Image:    "icons/pokeball.png",
+						ScaleY:   0.25,
+						Position: tt.position,
+					},
+				},
+			})
+			if err != nil {
+				t.Fatalf("failed to generate image: %v", err)
+			}
+			decoded, err := png.Decode(img)
+			if err != nil {
+				t.Fatalf("failed to decode image: %v", err)
+			}
+			if bounds := decoded.Bounds(); bounds.Dx() != 256 || bounds.Dy() != 128 {
+				t.Fatalf("unexpected size %dx%d", bounds.Dx(), bounds.Dy())
+			}
+			for y := range decoded.Bounds().Dy() {
+				for x := range decoded.Bounds().Dx() {
+					if r, g, b, _ := decoded.At(x, y).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+						if !image.Pt(x, y).In(tt.area) {
+							t.Fatalf("expected the layer in %v, found pixel at %d,%d", tt.area, x, y)
+						}
+					}
+				}
+			}
+			if !hasLayerPixel(decoded, tt.area) {
+				t.Fatalf("expected the layer in %v", tt.area)
+			}
+		})
+	}
+}
+
+// hasLayerPixel returns whether the area contains a pixel which isn't white.
+func hasLayerPixel(img image.Image, area image.Rectangle) bool {
+	for y := area.Min.Y; y < area.Max.Y; y++ {
+		for x := area.Min.X; x < area.Max.X; x++ {
+			if r, g, b, _ := img.At(x, y).RGBA(); r != 0xffff || g != 0xffff || b != 0xffff {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestGIFDelays(t *testing.T) {
+	tests := []struct {
+		frames int
+		fps    int
+		want   int
+	}{
+		{frames: 24, fps: 12, want: 200},
+		{frames: 10, fps: 10, want: 100},
+		{frames: 30, fps: 30, want: 100},
+		{frames: 7, fps: 7, want: 100},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%d frames at %d fps", tt.frames, tt.fps), func(t *testing.T) {
+			delays := gifDelays(tt.frames, tt.fps)
+			if len(delays) != tt.frames {
+				t.Fatalf("got %d delays, want %d", len(delays), tt.frames)
+			}
+			var total int
+			for _, delay := range delays {
+				total += delay
+			}
+			if total != tt.want {
+				t.Fatalf("got total duration %d, want %d: %v", total, tt.want, delays)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package icongen
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// metadataKeyword is the keyword of the iTXt chunk the metadata is stored in.
+const metadataKeyword = "pogo-icons"
+
+// maxMetadataChunkSize is the maximum length of an iTXt chunk ReadMetadata reads, the metadata is much smaller.
+const maxMetadataChunkSize = 1024 * 1024
+
+// generatorName is written into the Software text chunk next to the generator version.
+const generatorName = "pogo-icons"
+
+// ErrNoMetadata is returned by ReadMetadata if the image contains no metadata.
+var ErrNoMetadata = errors.New("no metadata found")
+
+// Metadata describes how an icon was generated. It is embedded into PNG and APNG icons, GIFs have no metadata.
+type Metadata struct {
+	Event     string   `json:"event"`
+	Pokemon   []string `json:"pokemon,omitempty"`
+	Cosmetics []string `json:"cosmetics,omitempty"`
+	Format    Format   `json:"format,omitempty"`
+	// Extra are additional parameters of the caller, e.g. the sprite style.
+	Extra map[string]string `json:"extra,omitempty"`
+	// ConfigVersion is the version of the config the icon was generated with. See Config.Version.
+	ConfigVersion string `json:"config_version,omitempty"`
+	// GeneratorVersion is the version of the program which generated the icon. See Options.Version.
+	GeneratorVersion string `json:"generator_version,omitempty"`
+}
+
+// String returns the metadata in a human-readable form, one parameter per line.
+func (m Metadata) String() string {
+	var sb strings.Builder
+	line := func(name string, value string) {
+		if value != "" {
+			_, _ = fmt.Fprintf(&sb, "%s: %s\n", name, value)
+		}
+	}
+	line("Event", m.Event)
+	line("Pokémon", strings.Join(m.Pokemon, ", "))
+	line("Cosmetics", strings.Join(m.Cosmetics, ", "))
+	line("Format", string(m.Format))
+	for _, key := range slices.Sorted(maps.Keys(m.Extra)) {
+		line(key, m.Extra[key])
+	}
+	line("Config version", m.ConfigVersion)
+	line("Generator version", m.GeneratorVersion)
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// embedMetadata inserts the metadata right after the IHDR chunk of the PNG. It is stored as JSON in an iTXt chunk for
+// ReadMetadata and as human-readable Description and Software text chunks for other tools.
+func embedMetadata(data []byte, metadata Metadata) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) || len(data) < len(pngSignature)+8 {
+		return nil, errors.New("failed to embed metadata: not a png")
+	}
+	ihdrEnd := len(pngSignature) + 12 + int(binary.BigEndian.Uint32(data[len(pngSignature):]))
+
+	text, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode metadata: %w", err)
+	}
+
+	buf := bytes.NewBuffer(make([]byte, 0, len(data)+2*len(text)+128))
+	buf.Write(data[:ihdrEnd])
+	writeITXtChunk(buf, metadataKeyword, string(text))
+	writeTextChunk(buf, "Description", metadata.String())
+	writeTextChunk(buf, "Software", strings.TrimSpace(generatorName+" "+metadata.GeneratorVersion))
+	buf.Write(data[ihdrEnd:])
+	return buf.Bytes(), nil
+}
+
+// writeTextChunk writes a tEXt chunk if the text is Latin-1 as required by the PNG spec, otherwise an uncompressed iTXt chunk.
+func writeTextChunk(buf *bytes.Buffer, keyword string, text string) {
+	latin1 := make([]byte, 0, len(text))
+	for _, r := range text {
+		if r > 0xff {
+			writeITXtChunk(buf, keyword, text)
+			return
+		}
+		latin1 = append(latin1, byte(r))
+	}
+	writeChunk(buf, "tEXt", append(append([]byte(keyword), 0), latin1...))
+}
+
+// writeITXtChunk writes an uncompressed iTXt chunk with UTF-8 text.
+func writeITXtChunk(buf *bytes.Buffer, keyword string, text string) {
+	// keyword, compression flag and method, empty language tag and translated keyword
+	itxt := append([]byte(keyword), 0, 0, 0, 0, 0)
+	writeChunk(buf, "iTXt", append(itxt, text...))
+}
+
+// ReadMetadata reads the metadata of an icon generated by Generate. Only the chunks before the image data are read.
+func ReadMetadata(r io.Reader) (Metadata, error) {
+	signature := make([]byte, len(pngSignature))
+	if _, err := io.ReadFull(r, signature); err != nil || !bytes.Equal(signature, pngSignature) {
+		return Metadata{}, ErrNoMetadata
+	}
+
+	header := make([]byte, 8)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			return Metadata{}, fmt.Errorf("failed to read chunk: %w", err)
+		}
+		length := binary.BigEndian.Uint32(header)
+		switch string(header[4:]) {
+		case "IDAT", "IEND":
+			return Metadata{}, ErrNoMetadata
+		case "iTXt":
+			if length > maxMetadataChunkSize {
+				return Metadata{}, fmt.Errorf("failed to read chunk: iTXt chunk too large: %d bytes", length)
+			}
+			data := make([]byte, int(length)+4)
+			if _, err := io.ReadFull(r, data); err != nil {
+				return Metadata{}, fmt.Errorf("failed to read chunk: %w", err)
+			}
+			keyword, rest, _ := bytes.Cut(data[:length], []byte{0})
+			if string(keyword) != metadataKeyword || len(rest) < 2 {
+				continue
+			}
+			// skip the compression flag and method, language tag and translated keyword
+			_, rest, _ = bytes.Cut(rest[2:], []byte{0})
+			_, text, _ := bytes.Cut(rest, []byte{0})
+
+			var metadata Metadata
+			if err := json.Unmarshal(text, &metadata); err != nil {
+				return Metadata{}, fmt.Errorf("failed to decode metadata: %w", err)
+			}
+			return metadata, nil
+		default:
+			if _, err := io.CopyN(io.Discard, r, int64(length)+4); err != nil {
+				return Metadata{}, fmt.Errorf("failed to read chunk: %w", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,215 @@
+package icongen
+
+import (
+	"image"
+	"image/color"
+	"slices"
+)
+
+const (
+	paletteSize = 4
+	// paletteSamples is the maximum number of pixels sampled per image to extract the palette.
+	paletteSamples = 20000
+)
+
+type colorBox struct {
+	pixels []color.NRGBA
+}
+
+func (b colorBox) channel(c color.NRGBA, ch int) uint8 {
+	switch ch {
+	case 0:
+		return c.R
+	case 1:
+		return c.G
+	default:
+		return c.B
+	}
+}
+
+// widest returns the channel with the biggest range and its range.
+func (b colorBox) widest() (int, int) {
+	var (
+		bestCh    int
+		bestRange int
+	)
+	for ch := range 3 {
+		lo, hi := uint8(255), uint8(0)
+		for _, p := range b.pixels {
+			v := b.channel(p, ch)
+			lo = min(lo, v)
+			hi = max(hi, v)
+		}
+		if r := int(hi) - int(lo); r > bestRange {
+			bestCh = ch
+			bestRange = r
+		}
+	}
+	return bestCh, bestRange
+}
+
+func (b colorBox) average() color.NRGBA {
+	var r, g, bl int
+	for _, p := range b.pixels {
+		r += int(p.R)
+		g += int(p.G)
+		bl += int(p.B)
+	}
+	n := len(b.pixels)
+	return color.NRGBA{R: uint8(r / n), G: uint8(g / n), B: uint8(bl / n), A: 0xFF}
+}
+
+// extractPalette returns up to n colors of the given images using median cut, ordered by how many pixels they represent.
+// Transparent pixels and near black outlines are ignored.
+func extractPalette(images []image.Image, n int) []color.NRGBA {
+	var pixels []color.NRGBA
+	for _, img := range images {
+		pixels = append(pixels, samplePixels(img, paletteSamples, func(c color.NRGBA) bool {
+			return c.A >= 0x80 && int(c.R)+int(c.G)+int(c.B) >= 0x60
+		})...)
+	}
+	return medianCut(pixels, n)
+}
+
+// medianCut reduces the pixels to up to n colors, ordered by how many pixels they represent.
+func medianCut(pixels []color.NRGBA, n int) []color.NRGBA {
+	if len(pixels) == 0 {
+		return nil
+	}
+
+	boxes := []colorBox{{pixels: pixels}}
+	for len(boxes) < n {
+		// split the box with the most pixels weighted by its color range
+		var (
+			best      = -1
+			bestScore int
+			bestCh    int
+		)
+		for i, box := range boxes {
+			if len(box.pixels) < 2 {
+				continue
+			}
+			ch, r := box.widest()
+			if score := r * len(box.pixels); score > bestScore {
+				best = i
+				bestScore = score
+				bestCh = ch
+			}
+		}
+		if best == -1 {
+			break
+		}
+
+		box := boxes[best]
+		slices.SortFunc(box.pixels, func(a color.NRGBA, b color.NRGBA) int {
+			return int(box.channel(a, bestCh)) - int(box.channel(b, bestCh))
+		})
+		mid := len(box.pixels) / 2
+		boxes[best] = colorBox{pixels: box.pixels[:mid]}
+		boxes = append(boxes, colorBox{pixels: box.pixels[mid:]})
+	}
+
+	slices.SortStableFunc(boxes, func(a colorBox, b colorBox) int {
+		return len(b.pixels) - len(a.pixels)
+	})
+	palette := make([]color.NRGBA, 0, len(boxes))
+	for _, box := range boxes {
+		palette = append(palette, box.average())
+	}
+	return palette
+}
+
+// samplePixels returns up to maxSamples evenly distributed pixels of the image which pass keep.
+func samplePixels(img image.Image, maxSamples int, keep func(c color.NRGBA) bool) []color.NRGBA {
+	bounds := img.Bounds()
+	step := 1
+	for (bounds.Dx()/step)*(bounds.Dy()/step) > maxSamples {
+		step++
+	}
+
+	var pixels []color.NRGBA
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if keep(c) {
+				pixels = append(pixels, c)
+			}
+		}
+	}
+	return pixels
+}
+
+func tintLayer(img image.Image, tint color.NRGBA, strength float64) image.Image {
+	bounds := img.Bounds()
+	newImg := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			tinted := lerpColor(c, tint, strength*float64(tint.A)/0xFF)
+			tinted.A = c.A
+			newImg.SetNRGBA(x, y, tinted)
+		}
+	}
+	return newImg
+}
+
+// glowLayer returns the silhouette of the image in the given color blurred by radius.
+// The returned image is padded by radius on every side.
+func glowLayer(img image.Image, glow color.NRGBA, radius int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx()+2*radius, bounds.Dy()+2*radius
+	alpha := make([]float64, w*h)
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			_, _, _, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			alpha[(y+radius)*w+x+radius] = float64(a) / 0xFFFF
+		}
+	}
+
+	// three box blurs approximate a gaussian blur
+	if radius > 0 {
+		boxRadius := max(radius/3, 1)
+		for range 3 {
+			alpha = boxBlur(alpha, w, h, boxRadius)
+		}
+	}
+
+	newImg := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for i, a := range alpha {
+		// boost the blurred alpha so the glow stays visible at the edges
+		a = min(a*2, 1) * float64(glow.A) / 0xFF
+		newImg.Pix[i*4] = glow.R
+		newImg.Pix[i*4+1] = glow.G
+		newImg.Pix[i*4+2] = glow.B
+		newImg.Pix[i*4+3] = uint8(a * 0xFF)
+	}
+	return newImg
+}
+
+func boxBlur(src []float64, w int, h int, radius int) []float64 {
+	tmp := make([]float64, len(src))
+	size := float64(2*radius + 1)
+	for y := range h {
+		var sum float64
+		for x := -radius; x <= radius; x++ {
+			sum += src[y*w+min(max(x, 0), w-1)]
+		}
+		for x := range w {
+			tmp[y*w+x] = sum / size
+			sum += src[y*w+min(x+radius+1, w-1)] - src[y*w+max(x-radius, 0)]
+		}
+	}
+
+	dst := make([]float64, len(src))
+	for x := range w {
+		var sum float64
+		for y := -radius; y <= radius; y++ {
+			sum += tmp[min(max(y, 0), h-1)*w+x]
+		}
+		for y := range h {
+			dst[y*w+x] = sum / size
+			sum += tmp[min(y+radius+1, h-1)*w+x] - tmp[max(y-radius, 0)*w+x]
+		}
+	}
+	return dst
+}
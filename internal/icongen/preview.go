@@ -0,0 +1,158 @@
+package icongen
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"io/fs"
+	"slices"
+
+	"golang.org/x/image/draw"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	defaultPreviewImage = "icons/pokeball.png"
+
+	defaultSheetColumns   = 4
+	defaultThumbnailWidth = 256
+	sheetPadding          = 8
+	sheetLabelSize        = 14
+)
+
+var (
+	sheetBackground = color.RGBA{R: 0x2b, G: 0x2d, B: 0x31, A: 0xff}
+	sheetLabelColor = color.RGBA{R: 0xf2, G: 0xf3, B: 0xf5, A: 0xff}
+)
+
+// SheetOptions configures the contact sheet of events.
+type SheetOptions struct {
+	// Columns is the number of thumbnails per row.
+	// Defaults to 4.
+	Columns int
+	// ThumbnailWidth is the width of each thumbnail in pixels, the height keeps the aspect ratio of the icons.
+	// Defaults to 256.
+	ThumbnailWidth int
+}
+
+// placeholderPokemon returns a PokemonFunc which returns the preview image for every Pokémon.
+func placeholderPokemon(assets fs.FS, cfg PreviewConfig) PokemonFunc {
+	return func(_ context.Context, _ string) (*Pokemon, error) {
+		r, err := assets.Open(cmp.Or(cfg.Image, defaultPreviewImage))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open preview image: %w", err)
+		}
+		types := cfg.Types
+		if len(types) == 0 {
+			types = []string{"normal"}
+		}
+		return &Pokemon{
+			Image: r,
+			Types: types,
+		}, nil
+	}
+}
+
+// Preview generates a PNG icon of the event with the cosmetics and placeholder Pokémon. If event is empty the preview
+// event of the config is used.
+func Preview(ctx context.Context, assets fs.FS, cfg Config, event string, cosmetics []string) (io.Reader, error) {
+	if event == "" {
+		event = cfg.Preview.Event
+	}
+	if event == "" && len(cfg.Events) > 0 {
+		event = cfg.Events[0].Name
+	}
+
+	pokemon := make([]string, min(max(cfg.Preview.Pokemon, 1), len(cfg.PokemonLayers)))
+	for i := range pokemon {
+		pokemon[i] = fmt.Sprintf("placeholder-%d", i+1)
+	}
+	return Generate(ctx, assets, cfg, placeholderPokemon(assets, cfg.Preview), event, pokemon, cosmetics, Options{})
+}
+
+// ContactSheet generates a PNG with a labeled preview thumbnail of each event.
+func ContactSheet(ctx context.Context, assets fs.FS, cfg Config, events []string, opts SheetOptions) (io.Reader, error) {
+	if len(events) == 0 {
+		return nil, fmt.Errorf("no events to preview")
+	}
+	columns := min(cmp.Or(opts.Columns, defaultSheetColumns), len(events))
+	thumbWidth := cmp.Or(opts.ThumbnailWidth, defaultThumbnailWidth)
+	thumbHeight := thumbWidth * cmp.Or(cfg.Height, defaultHeight) / cmp.Or(cfg.Width, defaultWidth)
+
+	face, err := labelFace()
+	if err != nil {
+		return nil, err
+	}
+	defer face.Close()
+
+	labelHeight := face.Metrics().Height.Ceil() + sheetPadding/2
+	cellWidth := thumbWidth + sheetPadding
+	cellHeight := thumbHeight + labelHeight + sheetPadding
+	rows := (len(events) + columns - 1) / columns
+
+	sheet := image.NewRGBA(image.Rect(0, 0, columns*cellWidth+sheetPadding, rows*cellHeight+sheetPadding))
+	draw.Draw(sheet, sheet.Bounds(), image.NewUniform(sheetBackground), image.Point{}, draw.Src)
+
+	for i, event := range events {
+		r, err := Preview(ctx, assets, cfg, event, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to preview event %q: %w", event, err)
+		}
+		icon, err := png.Decode(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode preview of event %q: %w", event, err)
+		}
+
+		x := sheetPadding + i%columns*cellWidth
+		y := sheetPadding + i/columns*cellHeight
+		draw.CatmullRom.Scale(sheet, image.Rect(x, y, x+thumbWidth, y+thumbHeight), icon, icon.Bounds(), draw.Over, nil)
+		drawLabel(sheet, face, event, image.Rect(x, y+thumbHeight, x+thumbWidth, y+thumbHeight+labelHeight))
+	}
+
+	buf := new(bytes.Buffer)
+	if err = png.Encode(buf, sheet); err != nil {
+		return nil, fmt.Errorf("failed to encode contact sheet: %w", err)
+	}
+	return bytes.NewReader(buf.Bytes()), nil
+}
+
+func labelFace() (font.Face, error) {
+	f, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse label font: %w", err)
+	}
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size: sheetLabelSize,
+		DPI:  72,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create label font face: %w", err)
+	}
+	return face, nil
+}
+
+// drawLabel draws the text centered in rect, shortening it with an ellipsis if it doesn't fit.
+func drawLabel(dst draw.Image, face font.Face, text string, rect image.Rectangle) {
+	d := &font.Drawer{
+		Dst:  dst,
+		Src:  image.NewUniform(sheetLabelColor),
+		Face: face,
+	}
+	runes := []rune(text)
+	for len(runes) > 1 && d.MeasureString(text).Ceil() > rect.Dx() {
+		runes = slices.Delete(runes, len(runes)-1, len(runes))
+		text = string(runes) + "…"
+	}
+
+	width := d.MeasureString(text).Ceil()
+	d.Dot = fixed.P(rect.Min.X+(rect.Dx()-width)/2, rect.Max.Y-face.Metrics().Descent.Ceil())
+	d.DrawString(text)
+}
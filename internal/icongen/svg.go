@@ -0,0 +1,36 @@
+package icongen
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/srwiley/oksvg"
+	"github.com/srwiley/rasterx"
+)
+
+// svgHeight is the height SVG sprites are rasterized at, it matches the size of the official artwork.
+const svgHeight = 475
+
+func isSVG(data []byte) bool {
+	head := data[:min(len(data), 512)]
+	return bytes.Contains(head, []byte("<svg")) || bytes.HasPrefix(bytes.TrimSpace(head), []byte("<?xml"))
+}
+
+func decodeSVG(data []byte) (image.Image, error) {
+	icon, err := oksvg.ReadIconStream(bytes.NewReader(data), oksvg.IgnoreErrorMode)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse svg: %w", err)
+	}
+	if icon.ViewBox.W <= 0 || icon.ViewBox.H <= 0 {
+		return nil, fmt.Errorf("svg has no size")
+	}
+
+	h := svgHeight
+	w := int(float64(h) * icon.ViewBox.W / icon.ViewBox.H)
+	icon.SetTarget(0, 0, float64(w), float64(h))
+
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	icon.Draw(rasterx.NewDasher(w, h, rasterx.NewScannerGV(w, h, img, img.Bounds())), 1)
+	return img, nil
+}
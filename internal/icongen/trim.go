@@ -0,0 +1,113 @@
+package icongen
+
+import (
+	"image"
+	"math"
+
+	"golang.org/x/image/draw"
+)
+
+const (
+	// trimAlpha is the alpha value below which pixels count as transparent when trimming.
+	trimAlpha = 0x10
+	// areaCoverage is the share of the icon height squared a Pokémon covers after area normalization.
+	areaCoverage = 0.5
+	// areaMinScale and areaMaxScale limit how much area normalization can shrink or grow a Pokémon.
+	areaMinScale = 0.7
+	areaMaxScale = 1.3
+)
+
+// visibleBounds returns the bounds of all pixels which are not transparent.
+func visibleBounds(img image.Image) image.Rectangle {
+	bounds := img.Bounds()
+	minX, minY := bounds.Max.X, bounds.Max.Y
+	maxX, maxY := bounds.Min.X, bounds.Min.Y
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if _, _, _, a := img.At(x, y).RGBA(); a>>8 < trimAlpha {
+				continue
+			}
+			minX, minY = min(minX, x), min(minY, y)
+			maxX, maxY = max(maxX, x+1), max(maxY, y+1)
+		}
+	}
+	if minX >= maxX || minY >= maxY {
+		return image.Rectangle{}
+	}
+	return image.Rect(minX, minY, maxX, maxY)
+}
+
+// trimLayer crops the image and all of its frames to the visible pixels of all frames.
+func trimLayer(layer imageLayer) imageLayer {
+	visible := visibleBounds(layer.Image)
+	for _, frame := range layer.Frames {
+		visible = visible.Union(visibleBounds(frame))
+	}
+	if visible.Empty() {
+		return layer
+	}
+
+	layer.Image = cropImage(layer.Image, visible)
+	frames := make([]image.Image, 0, len(layer.Frames))
+	for _, frame := range layer.Frames {
+		frames = append(frames, cropImage(frame, visible))
+	}
+	if len(frames) > 0 {
+		layer.Frames = frames
+	}
+	return layer
+}
+
+func cropImage(img image.Image, rect image.Rectangle) image.Image {
+	newImg := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(newImg, newImg.Bounds(), img, rect.Min, draw.Src)
+	return newImg
+}
+
+// heightScale returns the scale which makes the trimmed image of the given size as tall as the untrimmed box.
+func heightScale(box image.Point, size image.Point) float64 {
+	if box.Y == 0 || size.Y == 0 {
+		return 1
+	}
+	return float64(box.Y) / float64(size.Y)
+}
+
+// areaScale returns the scale which makes the visible area of the image cover areaCoverage of its height squared.
+// This way thin Pokémon are drawn bigger than bulky ones of the same height.
+func areaScale(img image.Image) float64 {
+	bounds := img.Bounds()
+	var visible float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			_, _, _, a := img.At(x, y).RGBA()
+			visible += float64(a) / 0xFFFF
+		}
+	}
+	if visible == 0 {
+		return 1
+	}
+	h := float64(bounds.Dy())
+	return min(max(math.Sqrt(areaCoverage*h*h/visible), areaMinScale), areaMaxScale)
+}
+
+// centerOffset returns how far the visual center of mass of the image is away from the center of its bounds.
+func centerOffset(img image.Image) image.Point {
+	bounds := img.Bounds()
+	var sumX, sumY, total float64
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			_, _, _, a := img.At(x, y).RGBA()
+			weight := float64(a) / 0xFFFF
+			sumX += float64(x-bounds.Min.X) * weight
+			sumY += float64(y-bounds.Min.Y) * weight
+			total += weight
+		}
+	}
+	if total == 0 {
+		return image.Point{}
+	}
+	return image.Point{
+		X: int(sumX/total - float64(bounds.Dx())/2),
+		Y: int(sumY/total - float64(bounds.Dy())/2),
+	}
+}
@@ -3,6 +3,7 @@ package pokeapi
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
@@ -73,31 +74,94 @@ func (c *clientAPI) getPokemonPage(ctx context.Context, url string) (Page[Pokemo
 }
 
 func (c *clientAPI) GetPokemonForm(ctx context.Context, name string) (PokemonForm, error) {
-	rq, err := http.NewRequestWithContext(ctx, http.MethodGet, c.endpoint+"/pokemon/"+name, nil)
+	form, _, err := c.getPokemonForm(ctx, name)
+	return form, err
+}
+
+// getPokemonForm returns the form and its species.
+func (c *clientAPI) getPokemonForm(ctx context.Context, name string) (PokemonForm, PokemonSpecie, error) {
+	var p Pokemon
+	if err := c.get(ctx, c.endpoint+"/pokemon/"+name, &p); errors.Is(err, ErrNotFound) {
+		return PokemonForm{}, PokemonSpecie{}, fmt.Errorf("failed to find pokemon: %w", ErrNotFound)
+	} else if err != nil {
+		return PokemonForm{}, PokemonSpecie{}, fmt.Errorf("error fetching pokemon: %w", err)
+	}
+
+	var specie PokemonSpecie
+	if err := c.get(ctx, p.Species.Url, &specie); err != nil {
+		return PokemonForm{}, PokemonSpecie{}, fmt.Errorf("error fetching specie: %w", err)
+	}
+
+	return specie.withSpecie(newPokemonForm(p)), specie, nil
+}
+
+// getDefaultForm returns the default variety of the species.
+func (c *clientAPI) getDefaultForm(ctx context.Context, specie PokemonSpecie) (PokemonForm, error) {
+	var p Pokemon
+	if err := c.get(ctx, c.endpoint+"/pokemon/"+specie.defaultVariety(), &p); err != nil {
+		return PokemonForm{}, fmt.Errorf("error fetching pokemon: %w", err)
+	}
+	return specie.withSpecie(newPokemonForm(p)), nil
+}
+
+func (c *clientAPI) GetEvolutionLine(ctx context.Context, name string) ([]PokemonForm, error) {
+	pf, specie, err := c.getPokemonForm(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var chain EvolutionChain
+	if err = c.get(ctx, specie.EvolutionChain.URL, &chain); err != nil {
+		return nil, fmt.Errorf("error fetching evolution chain: %w", err)
+	}
+
+	var forms []PokemonForm
+	for _, link := range chain.Line(specie.Name) {
+		// the species and form of the requested pokemon were already fetched
+		if link.Species.Name == specie.Name && pf.Default {
+			forms = append(forms, pf)
+			continue
+		}
+		stage := specie
+		if link.Species.Name != specie.Name {
+			stage = PokemonSpecie{}
+			if err = c.get(ctx, link.Species.URL, &stage); err != nil {
+				return nil, fmt.Errorf("error fetching specie: %w", err)
+			}
+		}
+		form, err := c.getDefaultForm(ctx, stage)
+		if err != nil {
+			return nil, err
+		}
+		forms = append(forms, form)
+	}
+	return forms, nil
+}
+
+func (c *clientAPI) get(ctx context.Context, url string, v any) error {
+	rq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
-		return PokemonForm{}, fmt.Errorf("error creating request: %w", err)
+		return fmt.Errorf("error creating request: %w", err)
 	}
 
 	rs, err := c.client.Do(rq)
 	if err != nil {
-		return PokemonForm{}, fmt.Errorf("error executing request: %w", err)
+		return fmt.Errorf("error executing request: %w", err)
 	}
 	defer rs.Body.Close()
 
 	if rs.StatusCode == http.StatusNotFound {
-		return PokemonForm{}, fmt.Errorf("failed to find pokemon: %w", ErrNotFound)
+		return ErrNotFound
 	}
 
 	if rs.StatusCode != http.StatusOK {
-		return PokemonForm{}, fmt.Errorf("error fetching pokemon: %w", fmt.Errorf("unexpected status code: %d", rs.StatusCode))
+		return fmt.Errorf("unexpected status code: %d", rs.StatusCode)
 	}
 
-	var p Pokemon
-	if err = json.NewDecoder(rs.Body).Decode(&p); err != nil {
-		return PokemonForm{}, fmt.Errorf("error decoding response: %w", err)
+	if err = json.NewDecoder(rs.Body).Decode(v); err != nil {
+		return fmt.Errorf("error decoding response: %w", err)
 	}
-
-	return newPokemonForm(p), nil
+	return nil
 }
 
 func (c *clientAPI) GetSprite(ctx context.Context, url string) (*http.Response, error) {
@@ -0,0 +1,71 @@
+package pokeapi
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+// oddishChain branches after Gloom into Vileplume and Bellossom.
+const oddishChain = `{"id": 18, "chain": {"species": {"name": "oddish", "url": "%[1]s/pokemon-species/oddish"}, "evolves_to": [
+	{"species": {"name": "gloom", "url": "%[1]s/pokemon-species/gloom"}, "evolves_to": [
+		{"species": {"name": "vileplume", "url": "%[1]s/pokemon-species/vileplume"}, "evolves_to": []},
+		{"species": {"name": "bellossom", "url": "%[1]s/pokemon-species/bellossom"}, "evolves_to": []}
+	]}
+]}}`
+
+func newTestAPI(t *testing.T) (Client, *atomic.Int32) {
+	var requests atomic.Int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		switch name, _ := strings.CutPrefix(r.URL.Path, "/pokemon-species/"); {
+		case r.URL.Path == "/evolution-chain/18":
+			_, _ = fmt.Fprintf(w, oddishChain, server.URL)
+		case name != r.URL.Path:
+			_, _ = fmt.Fprintf(w, `{"name": %[1]q, "evolution_chain": {"url": "%[2]s/evolution-chain/18"}, "varieties": [{"is_default": true, "pokemon": {"name": %[1]q}}]}`, name, server.URL)
+		default:
+			name = strings.TrimPrefix(r.URL.Path, "/pokemon/")
+			_, _ = fmt.Fprintf(w, `{"name": %[1]q, "species": {"name": %[1]q, "url": "%[2]s/pokemon-species/%[1]s"}}`, name, server.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return NewAPI(server.URL), &requests
+}
+
+func TestGetEvolutionLine(t *testing.T) {
+	tests := []struct {
+		name string
+		want []string
+		// maxRequests is the number of requests needed if every species and form is only fetched once
+		maxRequests int32
+	}{
+		{name: "oddish", want: []string{"oddish", "gloom"}, maxRequests: 5},
+		{name: "gloom", want: []string{"oddish", "gloom"}, maxRequests: 5},
+		{name: "vileplume", want: []string{"oddish", "gloom", "vileplume"}, maxRequests: 7},
+		{name: "bellossom", want: []string{"oddish", "gloom", "bellossom"}, maxRequests: 7},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, requests := newTestAPI(t)
+			forms, err := client.GetEvolutionLine(t.Context(), tt.name)
+			if err != nil {
+				t.Fatalf("failed to get evolution line: %v", err)
+			}
+			var got []string
+			for _, form := range forms {
+				got = append(got, form.Value)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+			if n := requests.Load(); n > tt.maxRequests {
+				t.Fatalf("got %d requests, want at most %d", n, tt.maxRequests)
+			}
+		})
+	}
+}
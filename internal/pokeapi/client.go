@@ -11,5 +11,8 @@ var ErrNotFound = errors.New("not found")
 type Client interface {
 	GetPokemon(ctx context.Context) ([]PokemonForm, error)
 	GetPokemonForm(ctx context.Context, name string) (PokemonForm, error)
+	// GetEvolutionLine returns the default forms of the species in the evolution line of the Pokémon ordered by evolution
+	// stage. Only the branch containing the Pokémon is followed, see EvolutionChain.Line.
+	GetEvolutionLine(ctx context.Context, name string) ([]PokemonForm, error)
 	GetSprite(ctx context.Context, url string) (*http.Response, error)
 }
@@ -63,6 +63,7 @@ type clientGit struct {
 	fs      billy.Filesystem
 	repo    *git.Repository
 	pokemon []PokemonForm
+	species map[string]PokemonSpecie
 }
 
 func (c *clientGit) load() error {
@@ -72,44 +73,62 @@ func (c *clientGit) load() error {
 	}
 
 	var pokemon []PokemonForm
+	pokemonSpecies := make(map[string]PokemonSpecie, len(species))
 	for _, specie := range species {
 		if !specie.IsDir() {
 			continue
 		}
 
-		pokemonSpecie, err := c.parseSpecie(specie)
+		p, forms, err := c.parseSpecie(specie)
 		if err != nil {
 			return fmt.Errorf("error parsing specie: %w", err)
 		}
-		pokemon = append(pokemon, pokemonSpecie...)
+		pokemon = append(pokemon, forms...)
+		pokemonSpecies[p.Name] = p
 	}
 	c.pokemon = pokemon
+	c.species = pokemonSpecies
 	return nil
 }
 
-func (c *clientGit) parseSpecie(specie os.FileInfo) ([]PokemonForm, error) {
+func (c *clientGit) parseSpecie(specie os.FileInfo) (PokemonSpecie, []PokemonForm, error) {
 	filename := path.Join("data/api/v2/pokemon-species", specie.Name(), "index.json")
 	file, err := c.fs.Open(filename)
 	if err != nil {
-		return nil, fmt.Errorf("error opening file %q: %w", filename, err)
+		return PokemonSpecie{}, nil, fmt.Errorf("error opening file %q: %w", filename, err)
 	}
 	defer file.Close()
 
 	var p PokemonSpecie
 	if err = json.NewDecoder(file).Decode(&p); err != nil {
-		return nil, fmt.Errorf("error decoding specie: %w", err)
+		return PokemonSpecie{}, nil, fmt.Errorf("error decoding specie: %w", err)
 	}
 
 	var forms []PokemonForm
 	for _, variety := range p.Varieties {
 		form, err := c.parsePokemon(variety.Pokemon.URL)
 		if err != nil {
-			return nil, fmt.Errorf("error parsing pokemon: %w", err)
+			return PokemonSpecie{}, nil, fmt.Errorf("error parsing pokemon: %w", err)
 		}
-		forms = append(forms, form)
+		forms = append(forms, p.withSpecie(form))
 	}
 
-	return forms, nil
+	return p, forms, nil
+}
+
+func (c *clientGit) parseEvolutionChain(url string) (EvolutionChain, error) {
+	file, err := c.fs.Open(path.Join("data", url, "index.json"))
+	if err != nil {
+		return EvolutionChain{}, fmt.Errorf("error opening file: %w", err)
+	}
+	defer file.Close()
+
+	var chain EvolutionChain
+	if err = json.NewDecoder(file).Decode(&chain); err != nil {
+		return EvolutionChain{}, fmt.Errorf("error decoding evolution chain: %w", err)
+	}
+
+	return chain, nil
 }
 
 func (c *clientGit) parsePokemon(url string) (PokemonForm, error) {
@@ -138,9 +157,47 @@ func (c *clientGit) GetPokemonForm(ctx context.Context, name string) (PokemonFor
 			return p, nil
 		}
 	}
+	for _, p := range c.pokemon {
+		for _, localized := range p.Names {
+			if strings.ToLower(localized) == name {
+				return p, nil
+			}
+		}
+	}
 	return PokemonForm{}, fmt.Errorf("pokemon not found: %w", ErrNotFound)
 }
 
+func (c *clientGit) GetEvolutionLine(ctx context.Context, name string) ([]PokemonForm, error) {
+	pf, err := c.GetPokemonForm(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	specie, ok := c.species[pf.Species]
+	if !ok {
+		return nil, fmt.Errorf("specie %q not found: %w", pf.Species, ErrNotFound)
+	}
+
+	chain, err := c.parseEvolutionChain(specie.EvolutionChain.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	var forms []PokemonForm
+	for _, link := range chain.Line(specie.Name) {
+		specie, ok = c.species[link.Species.Name]
+		if !ok {
+			return nil, fmt.Errorf("specie %q not found: %w", link.Species.Name, ErrNotFound)
+		}
+		form, err := c.GetPokemonForm(ctx, specie.defaultVariety())
+		if err != nil {
+			return nil, err
+		}
+		forms = append(forms, form)
+	}
+	return forms, nil
+}
+
 func (c *clientGit) GetSprite(ctx context.Context, url string) (*http.Response, error) {
 	rq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
 	if err != nil {
@@ -0,0 +1,123 @@
+package pokeapi
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// typeNames are the names of all Pokémon types.
+var typeNames = []string{
+	"normal", "fire", "water", "electric", "grass", "ice", "fighting", "poison", "ground",
+	"flying", "psychic", "bug", "rock", "ghost", "dragon", "dark", "steel", "fairy",
+}
+
+// regions maps the regions of regional forms to the words used to search for them.
+var regions = map[string][]string{
+	"alola":  {"alola", "alolan"},
+	"galar":  {"galar", "galarian"},
+	"hisui":  {"hisui", "hisuian"},
+	"paldea": {"paldea", "paldean"},
+}
+
+var romanNumerals = []string{"i", "ii", "iii", "iv", "v", "vi", "vii", "viii", "ix", "x"}
+
+// parseGeneration returns the number of a generation name like "generation-iii".
+func parseGeneration(name string) int {
+	numeral := strings.TrimPrefix(name, "generation-")
+	if i := slices.Index(romanNumerals, numeral); i != -1 {
+		return i + 1
+	}
+	return 0
+}
+
+// formRegion returns the region of a regional form like "vulpix-alola".
+func formRegion(name string) string {
+	parts := strings.Split(name, "-")
+	for region := range regions {
+		if slices.Contains(parts[1:], region) {
+			return region
+		}
+	}
+	return ""
+}
+
+// Filter returns the forms matching a query of Pokédex numbers like "#006", types like "fire", generations like "gen 3"
+// and regions like "alolan". Multiple terms can be combined, e.g. "fire gen 1".
+// ok is false if the query contains anything else, the query should be used as name search in that case.
+func Filter(forms []PokemonForm, query string) ([]PokemonForm, bool) {
+	var filters []func(f PokemonForm) bool
+	fields := strings.Fields(strings.ToLower(query))
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		if number, ok := strings.CutPrefix(field, "#"); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return nil, false
+			}
+			filters = append(filters, func(f PokemonForm) bool {
+				return f.Number == n
+			})
+			continue
+		}
+
+		if slices.Contains(typeNames, field) {
+			filters = append(filters, func(f PokemonForm) bool {
+				return slices.Contains(f.Types, field)
+			})
+			continue
+		}
+
+		if region, ok := searchRegion(field); ok {
+			filters = append(filters, func(f PokemonForm) bool {
+				return f.Region == region
+			})
+			continue
+		}
+
+		if generation, ok := strings.CutPrefix(field, "gen"); ok {
+			generation = strings.TrimPrefix(generation, "eration")
+			if generation == "" && i+1 < len(fields) {
+				i++
+				generation = fields[i]
+			}
+			n, err := strconv.Atoi(generation)
+			if err != nil {
+				n = slices.Index(romanNumerals, generation) + 1
+			}
+			if n < 1 {
+				return nil, false
+			}
+			filters = append(filters, func(f PokemonForm) bool {
+				return f.Generation == n
+			})
+			continue
+		}
+		return nil, false
+	}
+	if len(filters) == 0 {
+		return nil, false
+	}
+
+	var matches []PokemonForm
+	for _, form := range forms {
+		if !slices.ContainsFunc(filters, func(filter func(f PokemonForm) bool) bool {
+			return !filter(form)
+		}) {
+			matches = append(matches, form)
+		}
+	}
+	slices.SortStableFunc(matches, func(a PokemonForm, b PokemonForm) int {
+		return a.Number - b.Number
+	})
+	return matches, true
+}
+
+func searchRegion(word string) (string, bool) {
+	for region, words := range regions {
+		if slices.Contains(words, word) {
+			return region, true
+		}
+	}
+	return "", false
+}
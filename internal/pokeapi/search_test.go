@@ -0,0 +1,62 @@
+package pokeapi
+
+import (
+	"slices"
+	"testing"
+)
+
+var testForms = []PokemonForm{
+	{Value: "charizard", Number: 6, Types: []string{"fire", "flying"}, Generation: 1},
+	{Value: "bulbasaur", Number: 1, Types: []string{"grass", "poison"}, Generation: 1},
+	{Value: "vulpix", Number: 37, Types: []string{"fire"}, Generation: 1},
+	{Value: "vulpix-alola", Number: 37, Types: []string{"ice"}, Generation: 1, Region: "alola"},
+	{Value: "torchic", Number: 255, Types: []string{"fire"}, Generation: 3},
+	{Value: "gengar", Number: 94, Types: []string{"ghost", "poison"}, Generation: 1},
+}
+
+func TestFilter(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+		ok    bool
+	}{
+		{query: "#6", want: []string{"charizard"}, ok: true},
+		{query: "#006", want: []string{"charizard"}, ok: true},
+		{query: "#37", want: []string{"vulpix", "vulpix-alola"}, ok: true},
+		{query: "#999", want: nil, ok: true},
+		{query: "fire", want: []string{"charizard", "vulpix", "torchic"}, ok: true},
+		{query: "FIRE", want: []string{"charizard", "vulpix", "torchic"}, ok: true},
+		{query: "gen 3", want: []string{"torchic"}, ok: true},
+		{query: "gen3", want: []string{"torchic"}, ok: true},
+		{query: "generation iii", want: []string{"torchic"}, ok: true},
+		{query: "alolan", want: []string{"vulpix-alola"}, ok: true},
+		{query: "alola ice", want: []string{"vulpix-alola"}, ok: true},
+		{query: "fire gen 1", want: []string{"charizard", "vulpix"}, ok: true},
+		{query: "poison gen 1 #94", want: []string{"gengar"}, ok: true},
+		{query: "fire water", want: nil, ok: true},
+		// everything else is a name search
+		{query: "", ok: false},
+		{query: "charizard", ok: false},
+		{query: "gengar", ok: false},
+		{query: "fire charizard", ok: false},
+		{query: "#abc", ok: false},
+		{query: "gen", ok: false},
+		{query: "gen 0", ok: false},
+		{query: "gen xyz", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			forms, ok := Filter(testForms, tt.query)
+			if ok != tt.ok {
+				t.Fatalf("got ok %t, want %t", ok, tt.ok)
+			}
+			var got []string
+			for _, form := range forms {
+				got = append(got, form.Value)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,29 +1,255 @@
 package pokeapi
 
 import (
+	"fmt"
 	"strings"
 )
 
 func newPokemonForm(p Pokemon) PokemonForm {
+	types := make([]string, 0, len(p.Types))
+	for _, t := range p.Types {
+		types = append(types, t.Type.Name)
+	}
+
+	sprites := map[SpriteStyle]Sprite{
+		SpriteStyleOfficialArtwork: {
+			Default: p.Sprites.Other.OfficialArtwork.FrontDefault,
+			Shiny:   p.Sprites.Other.OfficialArtwork.FrontShiny,
+		},
+		SpriteStyleHome: {
+			Default:     p.Sprites.Other.Home.FrontDefault,
+			Shiny:       p.Sprites.Other.Home.FrontShiny,
+			Female:      p.Sprites.Other.Home.FrontFemale,
+			ShinyFemale: p.Sprites.Other.Home.FrontShinyFemale,
+		},
+		SpriteStyleShowdown: {
+			Default:     p.Sprites.Other.Showdown.FrontDefault,
+			Shiny:       p.Sprites.Other.Showdown.FrontShiny,
+			Female:      p.Sprites.Other.Showdown.FrontFemale,
+			ShinyFemale: p.Sprites.Other.Showdown.FrontShinyFemale,
+		},
+		SpriteStyleDreamWorld: {
+			Default: p.Sprites.Other.DreamWorld.FrontDefault,
+			Female:  p.Sprites.Other.DreamWorld.FrontFemale,
+		},
+		SpriteStylePixel: {
+			Default:     p.Sprites.FrontDefault,
+			Shiny:       p.Sprites.FrontShiny,
+			Female:      p.Sprites.FrontFemale,
+			ShinyFemale: p.Sprites.FrontShinyFemale,
+		},
+	}
+	for style, sprite := range sprites {
+		if sprite.Default == "" {
+			delete(sprites, style)
+		}
+	}
+
 	return PokemonForm{
 		Name:        strings.Title(strings.ReplaceAll(p.Name, "-", " ")),
 		Value:       p.Name,
 		Sprite:      p.Sprites.Other.OfficialArtwork.FrontDefault,
 		ShinySprite: p.Sprites.Other.OfficialArtwork.FrontShiny,
+		Species:     p.Species.Name,
+		Region:      formRegion(p.Name),
+		Sprites:     sprites,
+		Types:       types,
+		Height:      p.Height,
 	}
 }
 
+type SpriteStyle string
+
+const (
+	SpriteStyleOfficialArtwork SpriteStyle = "official-artwork"
+	SpriteStyleHome            SpriteStyle = "home"
+	// SpriteStyleShowdown sprites are animated GIFs.
+	SpriteStyleShowdown   SpriteStyle = "showdown"
+	SpriteStyleDreamWorld SpriteStyle = "dream-world"
+	SpriteStylePixel      SpriteStyle = "pixel"
+)
+
+// spriteFallbacks is the order in which styles are tried if a form has no sprite in the requested style.
+var spriteFallbacks = []SpriteStyle{
+	SpriteStyleOfficialArtwork,
+	SpriteStyleHome,
+	SpriteStylePixel,
+	SpriteStyleShowdown,
+	SpriteStyleDreamWorld,
+}
+
+type Sprite struct {
+	Default string
+	Shiny   string
+	// Female and ShinyFemale are only set for Pokémon which look different as females.
+	Female      string
+	ShinyFemale string
+}
+
+// URL returns the female and shiny sprite if requested and available, otherwise the default sprite.
+func (s Sprite) URL(female bool, shiny bool) string {
+	if shiny {
+		if female && s.ShinyFemale != "" {
+			return s.ShinyFemale
+		}
+		if s.Shiny != "" {
+			return s.Shiny
+		}
+	}
+	if female && s.Female != "" {
+		return s.Female
+	}
+	return s.Default
+}
+
+// FemaleSuffix is appended to a Pokémon name or ID to use its female sprites, e.g. "pyroar:female".
+const FemaleSuffix = ":female"
+
+// ParsePokemonName splits the female suffix off a Pokémon name or ID.
+func ParsePokemonName(name string) (string, bool) {
+	return strings.CutSuffix(name, FemaleSuffix)
+}
+
 type PokemonForm struct {
-	Name        string
-	Value       string
+	Name    string
+	Value   string
+	Species string
+	// Number is the national Pokédex number of the species.
+	Number int
+	// Generation is the generation the species was introduced in.
+	Generation int
+	// Region is the region of regional forms like "alola", empty for other forms.
+	Region string
+	// Default is whether the form is the default variety of its species.
+	Default     bool
 	Sprite      string
 	ShinySprite string
+	// Sprites are all sprite variants of the form by style. Styles without a sprite are omitted.
+	Sprites map[SpriteStyle]Sprite
+	// Names are the localized names of the form by PokéAPI language, e.g. "de" or "ja-Hrkt".
+	Names map[string]string
+	Types []string
+	// Height is the height of the form in decimetres.
+	Height int
+}
+
+// SpriteURL returns the sprite of the given style. If the form has no sprite in that style, the first available style of
+// official artwork, home, pixel, showdown and dream world is used instead.
+// Female and shiny sprites are used if requested and the style has one.
+func (f PokemonForm) SpriteURL(style SpriteStyle, female bool, shiny bool) string {
+	if sprite, ok := f.Sprites[style]; ok {
+		return sprite.URL(female, shiny)
+	}
+	for _, fallback := range spriteFallbacks {
+		if sprite, ok := f.Sprites[fallback]; ok {
+			return sprite.URL(female, shiny)
+		}
+	}
+	return f.Sprite
+}
+
+// LocalizedName returns the name of the form in the given PokéAPI language or the English name if there is none.
+func (f PokemonForm) LocalizedName(language string) string {
+	if name, ok := f.Names[language]; ok {
+		return name
+	}
+	return f.Name
+}
+
+// HasFemaleSprite returns whether the form has a female sprite in any style.
+func (f PokemonForm) HasFemaleSprite() bool {
+	for _, sprite := range f.Sprites {
+		if sprite.Female != "" {
+			return true
+		}
+	}
+	return false
 }
 
 func (f PokemonForm) FilterValue() string {
 	return f.Name
 }
 
+type EvolutionChain struct {
+	ID    int       `json:"id"`
+	Chain ChainLink `json:"chain"`
+}
+
+type ChainLink struct {
+	IsBaby  bool `json:"is_baby"`
+	Species struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"species"`
+	EvolvesTo []ChainLink `json:"evolves_to"`
+}
+
+// Line returns the links of the evolution line of the species ordered by evolution stage. Only the branch containing the
+// species is followed, later stages are added as long as the evolution doesn't branch. Eevee returns only Eevee, while
+// Vaporeon returns Eevee and Vaporeon. Nil is returned if the species is not part of the chain.
+func (c EvolutionChain) Line(species string) []ChainLink {
+	path := c.Chain.path(species)
+	if path == nil {
+		return nil
+	}
+	for last := path[len(path)-1]; len(last.EvolvesTo) == 1; last = path[len(path)-1] {
+		path = append(path, last.EvolvesTo[0])
+	}
+	return path
+}
+
+// path returns the links from this link to the species.
+func (l ChainLink) path(species string) []ChainLink {
+	if l.Species.Name == species {
+		return []ChainLink{l}
+	}
+	for _, next := range l.EvolvesTo {
+		if path := next.path(species); path != nil {
+			return append([]ChainLink{l}, path...)
+		}
+	}
+	return nil
+}
+
+// withSpecie returns the form with the data of its species.
+func (p PokemonSpecie) withSpecie(form PokemonForm) PokemonForm {
+	form.Names = p.localizedNames(form)
+	form.Number = p.ID
+	form.Generation = parseGeneration(p.Generation.Name)
+	form.Default = form.Value == p.defaultVariety()
+	return form
+}
+
+// localizedNames returns the names of the species in all languages. Names of forms which are not the default variety
+// get the form appended, e.g. "Glurak (Mega X)".
+func (p PokemonSpecie) localizedNames(form PokemonForm) map[string]string {
+	suffix := strings.TrimPrefix(form.Value, p.Name+"-")
+	if suffix == form.Value || form.Value == p.defaultVariety() {
+		suffix = ""
+	}
+	suffix = strings.Title(strings.ReplaceAll(suffix, "-", " "))
+
+	names := make(map[string]string, len(p.Names))
+	for _, name := range p.Names {
+		if suffix != "" {
+			names[name.Language.Name] = fmt.Sprintf("%s (%s)", name.Name, suffix)
+			continue
+		}
+		names[name.Language.Name] = name.Name
+	}
+	return names
+}
+
+// defaultVariety returns the name of the default Pokémon of the species.
+func (p PokemonSpecie) defaultVariety() string {
+	for _, variety := range p.Varieties {
+		if variety.IsDefault {
+			return variety.Pokemon.Name
+		}
+	}
+	return p.Name
+}
+
 type Page[T any] struct {
 	Count    int    `json:"count"`
 	Next     string `json:"next"`
@@ -164,19 +390,19 @@ type Pokemon struct {
 		BackShiny        string      `json:"back_shiny"`
 		BackShinyFemale  interface{} `json:"back_shiny_female"`
 		FrontDefault     string      `json:"front_default"`
-		FrontFemale      interface{} `json:"front_female"`
+		FrontFemale      string      `json:"front_female"`
 		FrontShiny       string      `json:"front_shiny"`
-		FrontShinyFemale interface{} `json:"front_shiny_female"`
+		FrontShinyFemale string      `json:"front_shiny_female"`
 		Other            struct {
 			DreamWorld struct {
-				FrontDefault interface{} `json:"front_default"`
-				FrontFemale  interface{} `json:"front_female"`
+				FrontDefault string `json:"front_default"`
+				FrontFemale  string `json:"front_female"`
 			} `json:"dream_world"`
 			Home struct {
-				FrontDefault     string      `json:"front_default"`
-				FrontFemale      interface{} `json:"front_female"`
-				FrontShiny       string      `json:"front_shiny"`
-				FrontShinyFemale interface{} `json:"front_shiny_female"`
+				FrontDefault     string `json:"front_default"`
+				FrontFemale      string `json:"front_female"`
+				FrontShiny       string `json:"front_shiny"`
+				FrontShinyFemale string `json:"front_shiny_female"`
 			} `json:"home"`
 			OfficialArtwork struct {
 				FrontDefault string `json:"front_default"`
@@ -188,9 +414,9 @@ type Pokemon struct {
 				BackShiny        interface{} `json:"back_shiny"`
 				BackShinyFemale  interface{} `json:"back_shiny_female"`
 				FrontDefault     string      `json:"front_default"`
-				FrontFemale      interface{} `json:"front_female"`
+				FrontFemale      string      `json:"front_female"`
 				FrontShiny       string      `json:"front_shiny"`
-				FrontShinyFemale interface{} `json:"front_shiny_female"`
+				FrontShinyFemale string      `json:"front_shiny_female"`
 			} `json:"showdown"`
 		} `json:"other"`
 		Versions struct {
@@ -9,6 +9,8 @@ import (
 	"os/signal"
 	"runtime/debug"
 	"syscall"
+	// the docker image has no timezone database for /schedule
+	_ "time/tzdata"
 
 	"github.com/BurntSushi/toml"
 	"github.com/charmbracelet/log"
@@ -16,6 +18,8 @@ import (
 	"github.com/disgoorg/disgo/bot"
 	"github.com/muesli/termenv"
 
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/i18n"
 	"github.com/topi314/pogo-icons/internal/icongen"
 	"github.com/topi314/pogo-icons/internal/pokeapi"
 	"github.com/topi314/pogo-icons/pogoicons"
@@ -69,13 +73,26 @@ func main() {
 		return
 	}
 
+	translator, err := i18n.Load(subAssets, "locales")
+	if err != nil {
+		slog.Error("Error while loading locales", slog.Any("err", err))
+		return
+	}
+
+	db, err := database.Open(cfg.Database.Path)
+	if err != nil {
+		slog.Error("Error while opening database", slog.Any("err", err))
+		return
+	}
+	defer db.Close()
+
 	pokeClient, err := pokeapi.NewGit(cfg.Repository, cfg.ClonePath)
 	if err != nil {
 		slog.Error("Error while creating pokeapi client", slog.Any("err", err))
 		return
 	}
 
-	b := pogoicons.New(client, pokeClient, cfg, version, goVersion, subAssets, assetCfg)
+	b := pogoicons.New(client, pokeClient, cfg, version, goVersion, subAssets, assetCfg, translator, db)
 	go b.Start()
 
 	slog.Info("Bot started")
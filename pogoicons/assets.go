@@ -0,0 +1,363 @@
+package pogoicons
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"slices"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+)
+
+const (
+	// guildAssetDir is the directory the assets of a guild are available in for icongen.
+	guildAssetDir = "guild/assets/"
+
+	maxGuildAssets       = 25
+	maxGuildAssetBytes   = 1024 * 1024
+	maxGuildAssetSize    = 1024
+	defaultGuildAssetPos = icongen.PositionTopRight
+	defaultGuildAssetScl = 0.25
+)
+
+// guildAssetNameRegex matches valid asset names, they are used as cosmetic names and in asset paths.
+var guildAssetNameRegex = regexp.MustCompile(`^[\w -]{2,32}$`)
+
+var guildAssetPositionChoices = []discord.ApplicationCommandOptionChoiceString{
+	{
+		Name:  "Top left",
+		Value: string(icongen.PositionTopLeft),
+	},
+	{
+		Name:  "Top",
+		Value: string(icongen.PositionTop),
+	},
+	{
+		Name:  "Top right",
+		Value: string(icongen.PositionTopRight),
+	},
+	{
+		Name:  "Left",
+		Value: string(icongen.PositionLeft),
+	},
+	{
+		Name:  "Center",
+		Value: string(icongen.PositionCenter),
+	},
+	{
+		Name:  "Right",
+		Value: string(icongen.PositionRight),
+	},
+	{
+		Name:  "Bottom left",
+		Value: string(icongen.PositionBottomLeft),
+	},
+	{
+		Name:  "Bottom",
+		Value: string(icongen.PositionBottom),
+	},
+	{
+		Name:  "Bottom right",
+		Value: string(icongen.PositionBottomRight),
+	},
+}
+
+// layeredFS opens files from the first file system which contains them.
+type layeredFS []fs.FS
+
+func (l layeredFS) Open(name string) (fs.File, error) {
+	for _, fsys := range l {
+		f, err := fsys.Open(name)
+		if err == nil {
+			return f, nil
+		}
+		if !errors.Is(err, fs.ErrNotExist) {
+			return nil, err
+		}
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// guildAssetsFS serves the images of the assets of a guild from the database in guildAssetDir.
+type guildAssetsFS struct {
+	db      *database.DB
+	guildID snowflake.ID
+}
+
+func (f guildAssetsFS) Open(name string) (fs.File, error) {
+	assetName, ok := strings.CutPrefix(name, guildAssetDir)
+	if !ok {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	}
+	data, err := f.db.GuildAssetImage(f.guildID, strings.TrimSuffix(assetName, ".png"))
+	if errors.Is(err, database.ErrNotFound) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+	} else if err != nil {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+	}
+	return fstest.MapFS{name: &fstest.MapFile{Data: data}}.Open(name)
+}
+
+func guildAssetPath(name string) string {
+	return guildAssetDir + strings.ToLower(name) + ".png"
+}
+
+// guildAssets returns the embedded assets with the assets of the guild on top.
+func (b *Bot) guildAssets(guildID *snowflake.ID) fs.FS {
+	if guildID == nil {
+		return b.assets
+	}
+	return layeredFS{
+		guildAssetsFS{db: b.db, guildID: *guildID},
+		b.assets,
+	}
+}
+
+// assetCosmetic returns the cosmetic drawing the asset.
+func assetCosmetic(asset database.GuildAsset) icongen.CosmeticConfig {
+	return icongen.CosmeticConfig{
+		Name: asset.Name,
+		Slot: asset.Slot,
+		Layers: []icongen.Layer{
+			{
+				ID:       icongen.LayerIDCosmetic,
+				Image:    guildAssetPath(asset.Name),
+				ScaleY:   asset.Scale,
+				Position: icongen.Position(asset.Position),
+			},
+		},
+	}
+}
+
+// iconConfig returns the icon config with the assets of the guild as additional cosmetics.
+func (b *Bot) iconConfig(ctx context.Context, guildID *snowflake.ID) icongen.Config {
+	if guildID == nil {
+		return b.iconCfg
+	}
+	assets, err := b.db.GuildAssets(*guildID)
+	if err != nil {
+		slog.ErrorContext(ctx, "error getting guild assets", slog.Any("err", err))
+		return b.iconCfg
+	}
+	if len(assets) == 0 {
+		return b.iconCfg
+	}
+
+	cfg := b.iconCfg
+	cfg.Cosmetics = slices.Clone(cfg.Cosmetics)
+	for _, asset := range assets {
+		cfg.Cosmetics = append(cfg.Cosmetics, assetCosmetic(asset))
+	}
+	return cfg
+}
+
+// readGuildAsset downloads the attachment and re-encodes it as PNG. Images bigger than maxGuildAssetSize are rejected.
+func readGuildAsset(ctx context.Context, attachment discord.Attachment) ([]byte, error) {
+	rq, err := http.NewRequestWithContext(ctx, http.MethodGet, attachment.URL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	rs, err := http.DefaultClient.Do(rq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download attachment: %w", err)
+	}
+	defer rs.Body.Close()
+	if rs.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download attachment: %s", rs.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(rs.Body, maxGuildAssetBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read attachment: %w", err)
+	}
+	return encodeGuildAsset(data)
+}
+
+// encodeGuildAsset re-encodes the image as PNG. The size is checked before decoding, so small files with huge dimensions
+// don't allocate the whole image.
+func encodeGuildAsset(data []byte) ([]byte, error) {
+	cfg, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if cfg.Width > maxGuildAssetSize || cfg.Height > maxGuildAssetSize {
+		return nil, fmt.Errorf("image is bigger than %dx%d pixels", maxGuildAssetSize, maxGuildAssetSize)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err = png.Encode(buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func (b *Bot) onAssetUpload(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	if !canManageGuild(e) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.missing_permissions"))
+	}
+
+	name := strings.TrimSpace(data.String("name"))
+	if !guildAssetNameRegex.MatchString(name) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.invalid_name"))
+	}
+	if _, ok := findCosmetic(b.iconCfg.Cosmetics, name); ok {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.name_taken", name))
+	}
+	assets, err := b.db.GuildAssets(*e.GuildID())
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error getting guild assets", slog.Any("err", err))
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.error", err))
+	}
+	replaced := slices.ContainsFunc(assets, func(asset database.GuildAsset) bool {
+		return strings.EqualFold(asset.Name, name)
+	})
+	if !replaced && len(assets) >= maxGuildAssets {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.too_many", maxGuildAssets))
+	}
+
+	attachment := data.Attachment("image")
+	if attachment.ContentType == nil || !strings.HasPrefix(*attachment.ContentType, "image/") {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.invalid_image", "not an image"))
+	}
+	if attachment.Size > maxGuildAssetBytes {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.too_large", maxGuildAssetBytes/1024))
+	}
+
+	asset := database.GuildAsset{
+		Name:       name,
+		GuildID:    *e.GuildID(),
+		Position:   string(defaultGuildAssetPos),
+		Scale:      defaultGuildAssetScl,
+		UploadedBy: e.User().ID,
+		UploadedAt: time.Now(),
+	}
+	if position, ok := data.OptString("position"); ok {
+		asset.Position = position
+	}
+	if scale, ok := data.OptFloat("scale"); ok {
+		asset.Scale = scale
+	}
+	// assets at the same position conflict by default
+	asset.Slot = asset.Position
+	if slot, ok := data.OptString("slot"); ok {
+		asset.Slot = slot
+	}
+
+	if err = e.DeferCreateMessage(true); err != nil {
+		return err
+	}
+
+	if asset.Image, err = readGuildAsset(e.Ctx, attachment); err != nil {
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "asset.invalid_image", err)),
+		})
+		return err
+	}
+	if err = b.db.SaveGuildAsset(asset); err != nil {
+		slog.ErrorContext(e.Ctx, "error saving guild asset", slog.Any("err", err))
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "asset.error", err)),
+		})
+		return err
+	}
+
+	update := discord.MessageUpdate{
+		Content: json.Ptr(b.translate(e.Locale(), "asset.uploaded", asset.Name)),
+	}
+	preview, err := icongen.Preview(e.Ctx, b.guildAssets(e.GuildID()), b.iconConfig(e.Ctx, e.GuildID()), "", []string{asset.Name})
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error generating asset preview", slog.Any("err", err))
+	} else {
+		update.Files = []*discord.File{
+			discord.NewFile("preview.png", "", preview),
+		}
+	}
+	_, err = e.UpdateInteractionResponse(update)
+	return err
+}
+
+func (b *Bot) onAssetList(_ discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	assets, err := b.db.GuildAssets(*e.GuildID())
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error getting guild assets", slog.Any("err", err))
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.error", err))
+	}
+	if len(assets) == 0 {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.empty"))
+	}
+
+	lines := make([]string, 0, len(assets))
+	for _, asset := range assets {
+		lines = append(lines, fmt.Sprintf("**%s**: %s, %.0f%%", asset.Name, asset.Position, asset.Scale*100))
+	}
+	return e.CreateMessage(discord.MessageCreate{
+		Embeds: []discord.Embed{
+			{
+				Title:       b.translate(e.Locale(), "asset.list_title"),
+				Description: truncate(strings.Join(lines, "\n"), 4096),
+			},
+		},
+		Flags: discord.MessageFlagEphemeral,
+	})
+}
+
+func (b *Bot) onAssetAutocomplete(e *handler.AutocompleteEvent) error {
+	assets, err := b.db.GuildAssets(*e.GuildID())
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error getting guild assets", slog.Any("err", err))
+		return e.AutocompleteResult([]discord.AutocompleteChoice{})
+	}
+
+	query := strings.ToLower(e.Data.String("name"))
+	choices := make([]discord.AutocompleteChoice, 0, maxChoices)
+	for _, asset := range assets {
+		if len(choices) >= maxChoices {
+			break
+		}
+		if !strings.Contains(strings.ToLower(asset.Name), query) {
+			continue
+		}
+		choices = append(choices, discord.AutocompleteChoiceString{
+			Name:  asset.Name,
+			Value: asset.Name,
+		})
+	}
+	return e.AutocompleteResult(choices)
+}
+
+func (b *Bot) onAssetDelete(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	if !canManageGuild(e) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.missing_permissions"))
+	}
+
+	name := data.String("name")
+	if err := b.db.DeleteGuildAsset(*e.GuildID(), name); err != nil {
+		if errors.Is(err, database.ErrNotFound) {
+			return b.replyEphemeral(e, b.translate(e.Locale(), "asset.not_found", name))
+		}
+		slog.ErrorContext(e.Ctx, "error deleting guild asset", slog.Any("err", err))
+		return b.replyEphemeral(e, b.translate(e.Locale(), "asset.error", err))
+	}
+	return b.replyEphemeral(e, b.translate(e.Locale(), "asset.deleted", name))
+}
@@ -2,18 +2,19 @@ package pogoicons
 
 import (
 	"context"
-	"io"
 	"io/fs"
 	"log/slog"
 
 	"github.com/disgoorg/disgo/bot"
 	"github.com/disgoorg/disgo/handler"
 
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/i18n"
 	"github.com/topi314/pogo-icons/internal/icongen"
 	"github.com/topi314/pogo-icons/internal/pokeapi"
 )
 
-func New(client *bot.Client, pokeClient pokeapi.Client, cfg Config, version string, goVersion string, assets fs.FS, iconCfg icongen.Config) *Bot {
+func New(client *bot.Client, pokeClient pokeapi.Client, cfg Config, version string, goVersion string, assets fs.FS, iconCfg icongen.Config, translator *i18n.Translator, db *database.DB) *Bot {
 	s := &Bot{
 		cfg:        cfg,
 		version:    version,
@@ -22,6 +23,9 @@ func New(client *bot.Client, pokeClient pokeapi.Client, cfg Config, version stri
 		iconCfg:    iconCfg,
 		client:     client,
 		pokeClient: pokeClient,
+		translator: translator,
+		previews:   newPreviewCache(),
+		db:         db,
 	}
 
 	client.AddEventListeners(s.routes())
@@ -37,6 +41,9 @@ type Bot struct {
 	iconCfg    icongen.Config
 	client     *bot.Client
 	pokeClient pokeapi.Client
+	translator *i18n.Translator
+	previews   *previewCache
+	db         *database.DB
 }
 
 func (b *Bot) Start() {
@@ -45,31 +52,39 @@ func (b *Bot) Start() {
 			slog.Info("Syncing commands")
 			commands, err := b.commands()
 			if err != nil {
-				b.client.Logger.Error("failed to sync commands", err)
+				b.client.Logger.Error("failed to sync commands", slog.Any("err", err))
 				return
 			}
 			if err = handler.SyncCommands(b.client, commands, b.cfg.Bot.GuildIDs); err != nil {
-				b.client.Logger.Error("failed to sync commands", err)
+				b.client.Logger.Error("failed to sync commands", slog.Any("err", err))
 			}
 		}()
 	}
 
 	if err := b.client.OpenGateway(context.Background()); err != nil {
-		b.client.Logger.Error("failed to open gateway", err)
+		b.client.Logger.Error("failed to open gateway", slog.Any("err", err))
 		return
 	}
 }
 
-func (b *Bot) getPokemonImage(ctx context.Context, p string) (io.ReadCloser, error) {
-	pf, err := b.pokeClient.GetPokemonForm(ctx, p)
-	if err != nil {
-		return nil, err
-	}
+// pokemonFunc resolves Pokémon using their sprite in the given style.
+func (b *Bot) pokemonFunc(style pokeapi.SpriteStyle, shiny bool) icongen.PokemonFunc {
+	return func(ctx context.Context, p string) (*icongen.Pokemon, error) {
+		name, female := pokeapi.ParsePokemonName(p)
+		pf, err := b.pokeClient.GetPokemonForm(ctx, name)
+		if err != nil {
+			return nil, err
+		}
 
-	rs, err := b.pokeClient.GetSprite(ctx, pf.Sprite)
-	if err != nil {
-		return nil, err
-	}
+		rs, err := b.pokeClient.GetSprite(ctx, pf.SpriteURL(style, female, shiny))
+		if err != nil {
+			return nil, err
+		}
 
-	return rs.Body, nil
+		return &icongen.Pokemon{
+			Image:  rs.Body,
+			Types:  pf.Types,
+			Height: pf.Height,
+		}, nil
+	}
 }
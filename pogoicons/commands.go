@@ -1,9 +1,13 @@
 package pogoicons
 
 import (
+	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"log/slog"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -11,34 +15,152 @@ import (
 	"github.com/disgoorg/disgo/discord"
 	"github.com/disgoorg/disgo/handler"
 	"github.com/disgoorg/disgo/handler/middleware"
+	"github.com/disgoorg/disgo/rest"
 	"github.com/disgoorg/json"
-	"go.gopad.dev/fuzzysearch/fuzzy"
+	"github.com/disgoorg/omit"
+	"github.com/disgoorg/snowflake/v2"
 
+	"github.com/topi314/pogo-icons/internal/database"
 	"github.com/topi314/pogo-icons/internal/icongen"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
 )
 
-func (b *Bot) commands() ([]discord.ApplicationCommandCreate, error) {
-	var eventChoices []discord.ApplicationCommandOptionChoiceString
-	for _, event := range b.iconCfg.Events {
-		eventChoices = append(eventChoices, discord.ApplicationCommandOptionChoiceString{
-			Name:  event.Name,
-			Value: event.Name,
-		})
-	}
+var formatChoices = []discord.ApplicationCommandOptionChoiceString{
+	{
+		Name:  "PNG",
+		Value: string(icongen.FormatPNG),
+	},
+	{
+		Name:  "GIF",
+		Value: string(icongen.FormatGIF),
+	},
+	{
+		Name:  "APNG",
+		Value: string(icongen.FormatAPNG),
+	},
+}
 
-	var cosmeticChoices []discord.ApplicationCommandOptionChoiceString
-	for _, cosmetic := range b.iconCfg.Cosmetics {
-		cosmeticChoices = append(cosmeticChoices, discord.ApplicationCommandOptionChoiceString{
-			Name:  cosmetic.Name,
-			Value: cosmetic.Name,
-		})
-	}
+var styleChoices = []discord.ApplicationCommandOptionChoiceString{
+	{
+		Name:  "Official Artwork",
+		Value: string(pokeapi.SpriteStyleOfficialArtwork),
+	},
+	{
+		Name:  "HOME",
+		Value: string(pokeapi.SpriteStyleHome),
+	},
+	{
+		Name:  "Showdown (animated)",
+		Value: string(pokeapi.SpriteStyleShowdown),
+	},
+	{
+		Name:  "Dream World",
+		Value: string(pokeapi.SpriteStyleDreamWorld),
+	},
+	{
+		Name:  "Pixel",
+		Value: string(pokeapi.SpriteStylePixel),
+	},
+}
 
+func (b *Bot) commands() ([]discord.ApplicationCommandCreate, error) {
 	return []discord.ApplicationCommandCreate{
 		discord.SlashCommandCreate{
-			Name:        "info",
-			Description: "Get some info about the bot",
+			Name:                     "info",
+			NameLocalizations:        b.localizations("commands.info.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.info.description"),
+			DescriptionLocalizations: b.localizations("commands.info.description"),
+			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
+				discord.ApplicationIntegrationTypeUserInstall,
+			},
+			Contexts: []discord.InteractionContextType{
+				discord.InteractionContextTypeGuild,
+				discord.InteractionContextTypeBotDM,
+				discord.InteractionContextTypePrivateChannel,
+			},
+		},
+		discord.SlashCommandCreate{
+			Name:                     "generate",
+			NameLocalizations:        b.localizations("commands.generate.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.description"),
+			DescriptionLocalizations: b.localizations("commands.generate.description"),
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionString{
+					Name:                     "event",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.event"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.event"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "preset",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.preset"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.preset"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "evolution_line",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.evolution_line"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.evolution_line"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "pokemon1",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.pokemon"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "pokemon2",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.pokemon"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "pokemon3",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.pokemon"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "pokemon4",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.pokemon"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "pokemon5",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.pokemon"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "pokemon6",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.pokemon"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "cosmetics",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.cosmetics"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.cosmetics"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "format",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.format"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.format"),
+					Choices:                  formatChoices,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "style",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.generate.options.style"),
+					DescriptionLocalizations: b.localizations("commands.generate.options.style"),
+					Choices:                  styleChoices,
+				},
+			},
 			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
 				discord.ApplicationIntegrationTypeUserInstall,
 			},
 			Contexts: []discord.InteractionContextType{
@@ -48,52 +170,545 @@ func (b *Bot) commands() ([]discord.ApplicationCommandCreate, error) {
 			},
 		},
 		discord.SlashCommandCreate{
-			Name:        "generate",
-			Description: "Generate a Pokémon GO event icon",
+			Name:                     "history",
+			NameLocalizations:        b.localizations("commands.history.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.history.description"),
+			DescriptionLocalizations: b.localizations("commands.history.description"),
 			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "list",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.history.list.description"),
+					DescriptionLocalizations: b.localizations("commands.history.list.description"),
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "rerun",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.history.rerun.description"),
+					DescriptionLocalizations: b.localizations("commands.history.rerun.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionInt{
+							Name:                     "icon",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.history.rerun.options.icon"),
+							DescriptionLocalizations: b.localizations("commands.history.rerun.options.icon"),
+							Required:                 true,
+							Autocomplete:             true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "event",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.history.rerun.options.event"),
+							DescriptionLocalizations: b.localizations("commands.history.rerun.options.event"),
+							Autocomplete:             true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "cosmetics",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.history.rerun.options.cosmetics"),
+							DescriptionLocalizations: b.localizations("commands.history.rerun.options.cosmetics"),
+							Autocomplete:             true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "format",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.history.rerun.options.format"),
+							DescriptionLocalizations: b.localizations("commands.history.rerun.options.format"),
+							Choices:                  formatChoices,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "style",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.history.rerun.options.style"),
+							DescriptionLocalizations: b.localizations("commands.history.rerun.options.style"),
+							Choices:                  styleChoices,
+						},
+					},
+				},
+			},
+			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
+				discord.ApplicationIntegrationTypeUserInstall,
+			},
+			Contexts: []discord.InteractionContextType{
+				discord.InteractionContextTypeGuild,
+				discord.InteractionContextTypeBotDM,
+				discord.InteractionContextTypePrivateChannel,
+			},
+		},
+		discord.SlashCommandCreate{
+			Name:                     "events",
+			NameLocalizations:        b.localizations("commands.events.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.events.description"),
+			DescriptionLocalizations: b.localizations("commands.events.description"),
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "gallery",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.events.gallery.description"),
+					DescriptionLocalizations: b.localizations("commands.events.gallery.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							Name:                     "category",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.events.gallery.options.category"),
+							DescriptionLocalizations: b.localizations("commands.events.gallery.options.category"),
+							Autocomplete:             true,
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "cosmetics",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.events.cosmetics.description"),
+					DescriptionLocalizations: b.localizations("commands.events.cosmetics.description"),
+				},
+			},
+			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
+				discord.ApplicationIntegrationTypeUserInstall,
+			},
+			Contexts: []discord.InteractionContextType{
+				discord.InteractionContextTypeGuild,
+				discord.InteractionContextTypeBotDM,
+				discord.InteractionContextTypePrivateChannel,
+			},
+		},
+		discord.SlashCommandCreate{
+			Name:                     "preset",
+			NameLocalizations:        b.localizations("commands.preset.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.description"),
+			DescriptionLocalizations: b.localizations("commands.preset.description"),
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "save",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.description"),
+					DescriptionLocalizations: b.localizations("commands.preset.save.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							Name:                     "name",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.name"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.name"),
+							Required:                 true,
+							MaxLength:                json.Ptr(50),
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "scope",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.scope"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.scope"),
+							Choices: []discord.ApplicationCommandOptionChoiceString{
+								{
+									Name:              b.translate(discord.LocaleEnglishUS, "preset.scope.user"),
+									NameLocalizations: b.localizations("preset.scope.user"),
+									Value:             string(database.PresetScopeUser),
+								},
+								{
+									Name:              b.translate(discord.LocaleEnglishUS, "preset.scope.guild"),
+									NameLocalizations: b.localizations("preset.scope.guild"),
+									Value:             string(database.PresetScopeGuild),
+								},
+							},
+						},
+						discord.ApplicationCommandOptionInt{
+							Name:                     "icon",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.icon"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.icon"),
+							Autocomplete:             true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "event",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.event"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.event"),
+							Autocomplete:             true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "pokemon",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.pokemon"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.pokemon"),
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "cosmetics",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.cosmetics"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.cosmetics"),
+							Autocomplete:             true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "format",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.format"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.format"),
+							Choices:                  formatChoices,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "style",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.style"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.style"),
+							Choices:                  styleChoices,
+						},
+						discord.ApplicationCommandOptionBool{
+							Name:                     "shiny",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.save.options.shiny"),
+							DescriptionLocalizations: b.localizations("commands.preset.save.options.shiny"),
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "load",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.load.description"),
+					DescriptionLocalizations: b.localizations("commands.preset.load.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							Name:                     "name",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.options.name"),
+							DescriptionLocalizations: b.localizations("commands.preset.options.name"),
+							Required:                 true,
+							Autocomplete:             true,
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "list",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.list.description"),
+					DescriptionLocalizations: b.localizations("commands.preset.list.description"),
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "delete",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.delete.description"),
+					DescriptionLocalizations: b.localizations("commands.preset.delete.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							Name:                     "name",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.preset.options.name"),
+							DescriptionLocalizations: b.localizations("commands.preset.options.name"),
+							Required:                 true,
+							Autocomplete:             true,
+						},
+					},
+				},
+			},
+			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
+				discord.ApplicationIntegrationTypeUserInstall,
+			},
+			Contexts: []discord.InteractionContextType{
+				discord.InteractionContextTypeGuild,
+				discord.InteractionContextTypeBotDM,
+				discord.InteractionContextTypePrivateChannel,
+			},
+		},
+		discord.SlashCommandCreate{
+			Name:                     "schedule",
+			NameLocalizations:        b.localizations("commands.schedule.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.description"),
+			DescriptionLocalizations: b.localizations("commands.schedule.description"),
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionString{
+					Name:                     "name",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.name"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.name"),
+					Required:                 true,
+					MaxLength:                json.Ptr(100),
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "start",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.start"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.start"),
+					Required:                 true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "event",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.event"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.event"),
+					Required:                 true,
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "end",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.end"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.end"),
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "description",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.description"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.description"),
+					MaxLength:                json.Ptr(1000),
+				},
+				discord.ApplicationCommandOptionChannel{
+					Name:                     "channel",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.channel"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.channel"),
+					ChannelTypes:             []discord.ChannelType{discord.ChannelTypeGuildVoice, discord.ChannelTypeGuildStageVoice},
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "location",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.location"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.location"),
+					MaxLength:                json.Ptr(100),
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "timezone",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.timezone"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.timezone"),
+				},
 				discord.ApplicationCommandOptionString{
-					Name:        "event",
-					Description: "The event this image is for",
-					Required:    true,
-					Choices:     eventChoices,
+					Name:                     "pokemon1",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.pokemon"),
+					Autocomplete:             true,
 				},
 				discord.ApplicationCommandOptionString{
-					Name:         "pokemon1",
-					Description:  "The Pokémon to include",
-					Autocomplete: true,
+					Name:                     "pokemon2",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.pokemon"),
+					Autocomplete:             true,
 				},
 				discord.ApplicationCommandOptionString{
-					Name:         "pokemon2",
-					Description:  "The Pokémon to include",
-					Autocomplete: true,
+					Name:                     "pokemon3",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.pokemon"),
+					Autocomplete:             true,
 				},
 				discord.ApplicationCommandOptionString{
-					Name:         "pokemon3",
-					Description:  "The Pokémon to include",
-					Autocomplete: true,
+					Name:                     "pokemon4",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.pokemon"),
+					Autocomplete:             true,
 				},
 				discord.ApplicationCommandOptionString{
-					Name:         "pokemon4",
-					Description:  "The Pokémon to include",
-					Autocomplete: true,
+					Name:                     "pokemon5",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.pokemon"),
+					Autocomplete:             true,
 				},
 				discord.ApplicationCommandOptionString{
-					Name:         "pokemon5",
-					Description:  "The Pokémon to include",
-					Autocomplete: true,
+					Name:                     "pokemon6",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.pokemon"),
+					Autocomplete:             true,
 				},
 				discord.ApplicationCommandOptionString{
-					Name:         "pokemon6",
-					Description:  "The Pokémon to include",
-					Autocomplete: true,
+					Name:                     "cosmetics",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.cosmetics"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.cosmetics"),
+					Autocomplete:             true,
 				},
 				discord.ApplicationCommandOptionString{
-					Name:        "cosmetic",
-					Description: "The cosmetic to use for the icon",
-					Choices:     cosmeticChoices,
+					Name:                     "style",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.schedule.options.style"),
+					DescriptionLocalizations: b.localizations("commands.schedule.options.style"),
+					Choices:                  styleChoices,
 				},
 			},
+			DefaultMemberPermissions: omit.NewPtr(discord.PermissionCreateEvents),
 			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
+			},
+			Contexts: []discord.InteractionContextType{
+				discord.InteractionContextTypeGuild,
+			},
+		},
+		discord.SlashCommandCreate{
+			Name:                     "emoji",
+			NameLocalizations:        b.localizations("commands.emoji.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.description"),
+			DescriptionLocalizations: b.localizations("commands.emoji.description"),
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionString{
+					Name:                     "event",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.options.event"),
+					DescriptionLocalizations: b.localizations("commands.emoji.options.event"),
+					Required:                 true,
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "pokemon",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.options.pokemon"),
+					DescriptionLocalizations: b.localizations("commands.emoji.options.pokemon"),
+					Required:                 true,
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "type",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.options.type"),
+					DescriptionLocalizations: b.localizations("commands.emoji.options.type"),
+					Choices:                  expressionTypeChoices,
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "name",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.options.name"),
+					DescriptionLocalizations: b.localizations("commands.emoji.options.name"),
+					MinLength:                json.Ptr(2),
+					MaxLength:                json.Ptr(maxEmojiNameLength),
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "cosmetics",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.options.cosmetics"),
+					DescriptionLocalizations: b.localizations("commands.emoji.options.cosmetics"),
+					Autocomplete:             true,
+				},
+				discord.ApplicationCommandOptionBool{
+					Name:                     "animated",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.options.animated"),
+					DescriptionLocalizations: b.localizations("commands.emoji.options.animated"),
+				},
+				discord.ApplicationCommandOptionBool{
+					Name:                     "shiny",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.options.shiny"),
+					DescriptionLocalizations: b.localizations("commands.emoji.options.shiny"),
+				},
+				discord.ApplicationCommandOptionString{
+					Name:                     "style",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.emoji.options.style"),
+					DescriptionLocalizations: b.localizations("commands.emoji.options.style"),
+					Choices:                  styleChoices,
+				},
+			},
+			DefaultMemberPermissions: omit.NewPtr(discord.PermissionCreateGuildExpressions),
+			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
+			},
+			Contexts: []discord.InteractionContextType{
+				discord.InteractionContextTypeGuild,
+			},
+		},
+		discord.SlashCommandCreate{
+			Name:                     "config",
+			NameLocalizations:        b.localizations("commands.config.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.config.description"),
+			DescriptionLocalizations: b.localizations("commands.config.description"),
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "show",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.config.show.description"),
+					DescriptionLocalizations: b.localizations("commands.config.show.description"),
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "set",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.config.set.description"),
+					DescriptionLocalizations: b.localizations("commands.config.set.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							Name:                     "default_event",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.config.set.options.default_event"),
+							DescriptionLocalizations: b.localizations("commands.config.set.options.default_event"),
+							Autocomplete:             true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "allowed_events",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.config.set.options.allowed_events"),
+							DescriptionLocalizations: b.localizations("commands.config.set.options.allowed_events"),
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "allowed_cosmetics",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.config.set.options.allowed_cosmetics"),
+							DescriptionLocalizations: b.localizations("commands.config.set.options.allowed_cosmetics"),
+							Autocomplete:             true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "branding",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.config.set.options.branding"),
+							DescriptionLocalizations: b.localizations("commands.config.set.options.branding"),
+							Choices:                  brandingChoices,
+						},
+						discord.ApplicationCommandOptionInt{
+							Name:                     "width",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.config.set.options.width"),
+							DescriptionLocalizations: b.localizations("commands.config.set.options.width"),
+							MinValue:                 json.Ptr(minWidth),
+							MaxValue:                 json.Ptr(maxWidth),
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "reset",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.config.reset.description"),
+					DescriptionLocalizations: b.localizations("commands.config.reset.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							Name:                     "setting",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.config.reset.options.setting"),
+							DescriptionLocalizations: b.localizations("commands.config.reset.options.setting"),
+							Choices:                  settingChoices,
+						},
+					},
+				},
+			},
+			DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
+			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
+			},
+			Contexts: []discord.InteractionContextType{
+				discord.InteractionContextTypeGuild,
+			},
+		},
+		discord.SlashCommandCreate{
+			Name:                     "asset",
+			NameLocalizations:        b.localizations("commands.asset.name"),
+			Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.description"),
+			DescriptionLocalizations: b.localizations("commands.asset.description"),
+			Options: []discord.ApplicationCommandOption{
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "upload",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.upload.description"),
+					DescriptionLocalizations: b.localizations("commands.asset.upload.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							Name:                     "name",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.upload.options.name"),
+							DescriptionLocalizations: b.localizations("commands.asset.upload.options.name"),
+							Required:                 true,
+							MinLength:                json.Ptr(2),
+							MaxLength:                json.Ptr(32),
+						},
+						discord.ApplicationCommandOptionAttachment{
+							Name:                     "image",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.upload.options.image"),
+							DescriptionLocalizations: b.localizations("commands.asset.upload.options.image"),
+							Required:                 true,
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "position",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.upload.options.position"),
+							DescriptionLocalizations: b.localizations("commands.asset.upload.options.position"),
+							Choices:                  guildAssetPositionChoices,
+						},
+						discord.ApplicationCommandOptionFloat{
+							Name:                     "scale",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.upload.options.scale"),
+							DescriptionLocalizations: b.localizations("commands.asset.upload.options.scale"),
+							MinValue:                 json.Ptr(0.05),
+							MaxValue:                 json.Ptr(1.0),
+						},
+						discord.ApplicationCommandOptionString{
+							Name:                     "slot",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.upload.options.slot"),
+							DescriptionLocalizations: b.localizations("commands.asset.upload.options.slot"),
+							MaxLength:                json.Ptr(32),
+						},
+					},
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "list",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.list.description"),
+					DescriptionLocalizations: b.localizations("commands.asset.list.description"),
+				},
+				discord.ApplicationCommandOptionSubCommand{
+					Name:                     "delete",
+					Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.delete.description"),
+					DescriptionLocalizations: b.localizations("commands.asset.delete.description"),
+					Options: []discord.ApplicationCommandOption{
+						discord.ApplicationCommandOptionString{
+							Name:                     "name",
+							Description:              b.translate(discord.LocaleEnglishUS, "commands.asset.delete.options.name"),
+							DescriptionLocalizations: b.localizations("commands.asset.delete.options.name"),
+							Required:                 true,
+							Autocomplete:             true,
+						},
+					},
+				},
+			},
+			DefaultMemberPermissions: omit.NewPtr(discord.PermissionManageGuild),
+			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
+			},
+			Contexts: []discord.InteractionContextType{
+				discord.InteractionContextTypeGuild,
+			},
+		},
+		discord.MessageCommandCreate{
+			Name:              "Remix icon",
+			NameLocalizations: b.localizations("commands.remix.name"),
+			IntegrationTypes: []discord.ApplicationIntegrationType{
+				discord.ApplicationIntegrationTypeGuildInstall,
 				discord.ApplicationIntegrationTypeUserInstall,
 			},
 			Contexts: []discord.InteractionContextType{
@@ -114,23 +729,73 @@ func (b *Bot) routes() bot.EventListener {
 		r.Autocomplete("/", b.onGenerateIconAutocomplete)
 		r.SlashCommand("/", b.onGenerateIcon)
 	})
+	r.Route("/history", func(r handler.Router) {
+		r.SlashCommand("/list", b.onHistoryList)
+		r.Autocomplete("/rerun", b.onHistoryRerunAutocomplete)
+		r.With(middleware.Defer(discord.InteractionTypeApplicationCommand, false, false)).SlashCommand("/rerun", b.onHistoryRerun)
+	})
+	r.Route("/events", func(r handler.Router) {
+		r.Autocomplete("/gallery", b.onCategoryAutocomplete)
+		r.With(middleware.Defer(discord.InteractionTypeApplicationCommand, false, false)).SlashCommand("/gallery", b.onEventsGallery)
+		r.With(middleware.Defer(discord.InteractionTypeApplicationCommand, false, true)).SlashCommand("/cosmetics", b.onEventsCosmetics)
+		r.ButtonComponent("/cosmetics/{page}", b.onEventsCosmeticsPage)
+	})
+	r.Route("/icon/{id}", func(r handler.Router) {
+		r.SelectMenuComponent("/event", b.onIconEvent)
+		r.SelectMenuComponent("/cosmetics", b.onIconCosmetics)
+		r.ButtonComponent("/shiny", b.onIconShiny)
+		r.ButtonComponent("/reorder", b.onIconReorder)
+		r.ButtonComponent("/rerender", b.onIconRerender)
+	})
+	r.Route("/preset", func(r handler.Router) {
+		r.Autocomplete("/save", b.onHistoryRerunAutocomplete)
+		r.SlashCommand("/save", b.onPresetSave)
+		r.Autocomplete("/load", b.onPresetAutocomplete)
+		r.With(middleware.Defer(discord.InteractionTypeApplicationCommand, false, false)).SlashCommand("/load", b.onPresetLoad)
+		r.SlashCommand("/list", b.onPresetList)
+		r.Autocomplete("/delete", b.onPresetAutocomplete)
+		r.SlashCommand("/delete", b.onPresetDelete)
+	})
+	r.Autocomplete("/schedule", b.onGenerateIconAutocomplete)
+	r.SlashCommand("/schedule", b.onSchedule)
+	r.Autocomplete("/emoji", b.onGenerateIconAutocomplete)
+	r.SlashCommand("/emoji", b.onEmoji)
+	r.Route("/config", func(r handler.Router) {
+		r.SlashCommand("/show", b.onConfigShow)
+		r.Autocomplete("/set", b.onConfigAutocomplete)
+		r.SlashCommand("/set", b.onConfigSet)
+		r.SlashCommand("/reset", b.onConfigReset)
+	})
+	r.Route("/asset", func(r handler.Router) {
+		r.SlashCommand("/upload", b.onAssetUpload)
+		r.SlashCommand("/list", b.onAssetList)
+		r.Autocomplete("/delete", b.onAssetAutocomplete)
+		r.SlashCommand("/delete", b.onAssetDelete)
+	})
+	r.MessageCommand("/Remix icon", b.onRemixIcon)
+	r.Modal("/remix/{shiny}", b.onRemixSubmit)
 
 	return r
 }
 
 func (b *Bot) onInfo(_ discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
 	return e.CreateMessage(discord.MessageCreate{
-		Content: fmt.Sprintf("PogoIcons is a bot that generates evemt icons for Pokémon GO.\n\n**Version:** `%s`\n**Go Version:** `%s`\n",
-			b.version,
-			b.goVersion,
-		),
-		Flags: discord.MessageFlagEphemeral,
+		Content: b.translate(e.Locale(), "info.message", b.version, b.goVersion),
+		Flags:   discord.MessageFlagEphemeral,
 	})
 }
 
 func (b *Bot) onGenerateIconAutocomplete(e *handler.AutocompleteEvent) error {
 	opt := e.Data.Focused()
-	value := e.Data.String(opt.Name)
+	switch opt.Name {
+	case "event":
+		return b.onEventAutocomplete(e)
+	case "preset":
+		return b.onPresetAutocomplete(e)
+	case "cosmetics":
+		return b.onCosmeticsAutocomplete(e)
+	}
+	value, onlyFemale := pokeapi.ParsePokemonName(e.Data.String(opt.Name))
 
 	pokemon, err := b.pokeClient.GetPokemon(e.Ctx)
 	if err != nil {
@@ -138,27 +803,67 @@ func (b *Bot) onGenerateIconAutocomplete(e *handler.AutocompleteEvent) error {
 		return e.AutocompleteResult([]discord.AutocompleteChoice{})
 	}
 
-	ranks := fuzzy.RankFindNormalizedFold(value, pokemon)
-	if len(ranks) == 0 {
-		return e.AutocompleteResult([]discord.AutocompleteChoice{})
+	if onlyFemale {
+		pokemon = slices.DeleteFunc(slices.Clone(pokemon), func(form pokeapi.PokemonForm) bool {
+			return !form.HasFemaleSprite()
+		})
 	}
-	choices := make([]discord.AutocompleteChoice, 0, max(25, len(ranks)))
-	for i, rank := range ranks {
-		if i >= 25 {
+
+	// search the names in all languages but show them in the language of the user
+	forms := searchPokemon(pokemon, value, b.usageScore(e.Ctx, e.User().ID, e.GuildID()))
+	lang := language(e.Locale())
+	choices := make([]discord.AutocompleteChoice, 0, maxChoices)
+	for _, form := range forms {
+		name := form.LocalizedName(lang)
+		if !onlyFemale {
+			choices = append(choices, discord.AutocompleteChoiceString{
+				Name:  name,
+				Value: form.Value,
+			})
+		}
+		if opt.Name != "evolution_line" && form.HasFemaleSprite() {
+			choices = append(choices, discord.AutocompleteChoiceString{
+				Name:  fmt.Sprintf("%s (%s)", name, b.translate(e.Locale(), "generate.female")),
+				Value: form.Value + pokeapi.FemaleSuffix,
+			})
+		}
+		if len(choices) >= maxChoices {
+			choices = choices[:maxChoices]
 			break
 		}
-		choices = append(choices, discord.AutocompleteChoiceString{
-			Name:  rank.Target.Name,
-			Value: rank.Target.Value,
-		})
 	}
 
 	return e.AutocompleteResult(choices)
 }
 
 func (b *Bot) onGenerateIcon(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
-	event := data.String("event")
+	var params generateParams
+	if name, ok := data.OptString("preset"); ok {
+		preset, err := b.findPreset(e.User().ID, e.GuildID(), name)
+		if err != nil {
+			_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+				Content: json.Ptr(b.translate(e.Locale(), "preset.not_found", name)),
+			})
+			return err
+		}
+		params = presetParams(preset)
+	}
+
 	var pokemonList []string
+	if evolutionLine, ok := data.OptString("evolution_line"); ok {
+		name, _ := pokeapi.ParsePokemonName(evolutionLine)
+		forms, err := b.pokeClient.GetEvolutionLine(e.Ctx, name)
+		if err != nil {
+			slog.ErrorContext(e.Ctx, "error getting evolution line", slog.Any("err", err))
+			_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+				Content: json.Ptr(b.translate(e.Locale(), "generate.evolution_line_error", err)),
+			})
+			return err
+		}
+		for _, form := range forms {
+			pokemonList = append(pokemonList, form.Value)
+		}
+	}
 	if pokemon, ok := data.OptString("pokemon1"); ok {
 		pokemonList = append(pokemonList, pokemon)
 	}
@@ -177,27 +882,121 @@ func (b *Bot) onGenerateIcon(data discord.SlashCommandInteractionData, e *handle
 	if pokemon, ok := data.OptString("pokemon6"); ok {
 		pokemonList = append(pokemonList, pokemon)
 	}
-	var cosmetics []string
-	if cosmetic, ok := data.OptString("cosmetic"); ok {
-		cosmetics = append(cosmetics, cosmetic)
+	if len(pokemonList) > 0 {
+		params.Pokemon = pokemonList
+	}
+	if len(params.Pokemon) > len(b.iconCfg.PokemonLayers) {
+		_, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "generate.too_many_pokemon", len(b.iconCfg.PokemonLayers))),
+		})
+		return err
+	}
+	if event, ok := data.OptString("event"); ok {
+		params.Event = b.parseEvent(event)
+	}
+	if cosmetics, ok := data.OptString("cosmetics"); ok {
+		params.Cosmetics = parseCosmetics(b.iconConfig(e.Ctx, e.GuildID()), cosmetics)
+	}
+	if format, ok := data.OptString("format"); ok {
+		params.Format = icongen.Format(format)
 	}
+	if style, ok := data.OptString("style"); ok {
+		params.Style = pokeapi.SpriteStyle(style)
+	}
+	if params.Event == "" {
+		params.Event = b.guildConfig(e.Ctx, e.GuildID()).DefaultEvent
+	}
+	if params.Event == "" {
+		_, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "generate.missing_event")),
+		})
+		return err
+	}
+
+	return b.generate(e.Ctx, e, params)
+}
 
-	ctx, cancel := context.WithTimeout(e.Ctx, 30*time.Second)
+// generateParams are the parameters of a generated icon.
+type generateParams struct {
+	Event     string
+	Pokemon   []string
+	Cosmetics []string
+	Format    icongen.Format
+	Style     pokeapi.SpriteStyle
+	Shiny     bool
+}
+
+// deferredEvent is a deferred command or component interaction which is responded to by updating the response.
+type deferredEvent interface {
+	User() discord.User
+	GuildID() *snowflake.ID
+	Locale() discord.Locale
+	UpdateInteractionResponse(messageUpdate discord.MessageUpdate, opts ...rest.RequestOpt) (*discord.Message, error)
+}
+
+// withDefaultStyle returns the parameters with the official artwork as style if none is set, or the Showdown sprites
+// for animated formats.
+func (p generateParams) withDefaultStyle() generateParams {
+	if p.Style == "" {
+		p.Style = pokeapi.SpriteStyleOfficialArtwork
+		if p.Format.Animated() {
+			p.Style = pokeapi.SpriteStyleShowdown
+		}
+	}
+	return p
+}
+
+// renderIcon generates the icon with the parameters and the config of the guild and records the usage of the Pokémon.
+// The format, version and metadata of the options are set from the parameters.
+func (b *Bot) renderIcon(ctx context.Context, e deferredEvent, params generateParams, opts icongen.Options) ([]byte, error) {
+	generateCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
-	icon, err := icongen.Generate(ctx, b.assets, b.iconCfg, b.getPokemonImage, event, pokemonList, cosmetics)
+	assets, opts, err := b.applyGuildConfig(ctx, e.GuildID(), params, opts)
+	if err != nil {
+		return nil, err
+	}
+	opts.Format = params.Format
+	opts.Version = b.version
+	opts.Metadata = map[string]string{
+		metadataStyle: string(params.Style),
+		metadataShiny: strconv.FormatBool(params.Shiny),
+	}
+	icon, err := icongen.Generate(generateCtx, assets, b.iconConfig(ctx, e.GuildID()), b.pokemonFunc(params.Style, params.Shiny), params.Event, params.Pokemon, params.Cosmetics, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := io.ReadAll(icon)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read icon: %w", err)
+	}
+	b.recordUsage(ctx, e.User().ID, e.GuildID(), params.Pokemon)
+	return data, nil
+}
+
+// generate generates the icon, stores it in the history and responds to the deferred interaction with it and the
+// components to edit it.
+func (b *Bot) generate(ctx context.Context, e deferredEvent, params generateParams) error {
+	params = params.withDefaultStyle()
+	data, err := b.renderIcon(ctx, e, params, icongen.Options{})
 	if err != nil {
-		slog.ErrorContext(e.Ctx, "error generating icon", slog.Any("err", err))
+		slog.ErrorContext(ctx, "error generating icon", slog.Any("err", err))
 		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
-			Content: json.Ptr(fmt.Sprintf("Error generating icon: %s", err)),
+			Content: json.Ptr(b.translate(e.Locale(), "generate.error", err)),
 		})
 		return err
 	}
+	id := b.addHistory(ctx, e, params, data)
 
+	names := b.localizedPokemonNames(ctx, e.Locale(), params.Pokemon)
 	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
-		Content: json.Ptr(fmt.Sprintf("Generated icon for `%s` with `%s`", event, strings.Join(pokemonList, ", "))),
+		Content:    json.Ptr(b.translate(e.Locale(), "generate.success", params.Event, strings.Join(names, ", "))),
+		Components: json.Ptr(b.iconComponents(ctx, e.Locale(), e.GuildID(), id, params)),
+		// replace the previous icon when the message is edited through its components
+		Attachments: &[]discord.AttachmentUpdate{},
 		Files: []*discord.File{
-			discord.NewFile(fmt.Sprintf("%s_%s.png", strings.ReplaceAll(strings.ToLower(event), " ", "_"), strings.Join(pokemonList, "_")), "", icon),
+			discord.NewFile(fmt.Sprintf("%s_%s.%s", strings.ReplaceAll(strings.ToLower(params.Event), " ", "_"), strings.ReplaceAll(strings.ToLower(strings.Join(names, "_")), " ", "_"), params.Format.Extension()), "", bytes.NewReader(data)),
 		},
 	})
 
@@ -0,0 +1,175 @@
+package pogoicons
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strconv"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+)
+
+// maxSelectOptions is the maximum number of options Discord allows in a select menu.
+const maxSelectOptions = 25
+
+// iconComponents returns the components to edit the generated icon with the given history ID. The custom IDs reference
+// the generation in the history, so no state has to be kept in memory. Icons which are not in the history can't be edited.
+// The selects only offer the events and cosmetics allowed by the config of the guild, including the assets of the guild.
+func (b *Bot) iconComponents(ctx context.Context, locale discord.Locale, guildID *snowflake.ID, id uint64, params generateParams) []discord.LayoutComponent {
+	if id == 0 {
+		return []discord.LayoutComponent{}
+	}
+	cfg := b.guildConfig(ctx, guildID)
+	prefix := "/icon/" + strconv.FormatUint(id, 10)
+
+	var components []discord.LayoutComponent
+
+	events := b.selectEvents(cfg, params.Event)
+	eventOptions := make([]discord.StringSelectMenuOption, 0, len(events))
+	for _, event := range events {
+		option := discord.NewStringSelectMenuOption(event.Name, event.Name)
+		option.Default = event.Name == params.Event
+		eventOptions = append(eventOptions, option)
+	}
+	components = append(components, discord.NewActionRow(
+		discord.NewStringSelectMenu(prefix+"/event", b.translate(locale, "icon.event"), eventOptions...),
+	))
+
+	cosmetics := slices.DeleteFunc(slices.Clone(b.iconConfig(ctx, guildID).Cosmetics), func(cosmetic icongen.CosmeticConfig) bool {
+		return !cosmeticAllowed(cfg, cosmetic.Name)
+	})
+	if len(cosmetics) > 0 {
+		cosmeticOptions := make([]discord.StringSelectMenuOption, 0, min(len(cosmetics), maxSelectOptions))
+		for _, cosmetic := range cosmetics[:min(len(cosmetics), maxSelectOptions)] {
+			option := discord.NewStringSelectMenuOption(cosmetic.Name, cosmetic.Name)
+			option.Default = slices.Contains(params.Cosmetics, cosmetic.Name)
+			cosmeticOptions = append(cosmeticOptions, option)
+		}
+		components = append(components, discord.NewActionRow(
+			discord.NewStringSelectMenu(prefix+"/cosmetics", b.translate(locale, "icon.cosmetics"), cosmeticOptions...).
+				WithMinValues(0).
+				WithMaxValues(len(cosmeticOptions)),
+		))
+	}
+
+	shiny := discord.NewSecondaryButton(b.translate(locale, "icon.shiny"), prefix+"/shiny")
+	if params.Shiny {
+		shiny = shiny.WithStyle(discord.ButtonStyleSuccess)
+	}
+	buttons := []discord.InteractiveComponent{shiny}
+	if len(params.Pokemon) > 1 {
+		buttons = append(buttons, discord.NewSecondaryButton(b.translate(locale, "icon.reorder"), prefix+"/reorder"))
+	}
+	buttons = append(buttons, discord.NewPrimaryButton(b.translate(locale, "icon.rerender"), prefix+"/rerender"))
+
+	return append(components, discord.NewActionRow(buttons...))
+}
+
+// selectEvents returns the events allowed by the config shown in the event select menu. If there are more events than fit,
+// the current event and the events of its category are shown first.
+func (b *Bot) selectEvents(cfg database.GuildConfig, current string) []icongen.EventConfig {
+	events := slices.DeleteFunc(slices.Clone(b.iconCfg.Events), func(event icongen.EventConfig) bool {
+		return !eventAllowed(cfg, event.Name)
+	})
+	if len(events) <= maxSelectOptions {
+		return events
+	}
+
+	currentEvent, _ := b.iconCfg.Event(current)
+	rank := func(event icongen.EventConfig) int {
+		switch {
+		case event.Name == currentEvent.Name:
+			return 0
+		case currentEvent.Category != "" && event.Category == currentEvent.Category:
+			return 1
+		default:
+			return 2
+		}
+	}
+	slices.SortStableFunc(events, func(a icongen.EventConfig, b icongen.EventConfig) int {
+		return cmp.Compare(rank(a), rank(b))
+	})
+	return events[:maxSelectOptions]
+}
+
+func (b *Bot) onIconEvent(_ discord.SelectMenuInteractionData, e *handler.ComponentEvent) error {
+	values := e.StringSelectMenuInteractionData().Values
+	return b.editIcon(e, func(params *generateParams) {
+		params.Event = values[0]
+	})
+}
+
+// onIconCosmetics replaces the cosmetics, conflicting cosmetics are rejected before generating as the select menu can't prevent them.
+func (b *Bot) onIconCosmetics(_ discord.SelectMenuInteractionData, e *handler.ComponentEvent) error {
+	values := e.StringSelectMenuInteractionData().Values
+	if err := b.iconConfig(e.Ctx, e.GuildID()).CheckCosmetics(values); err != nil {
+		return e.CreateMessage(discord.MessageCreate{
+			Content: b.translate(e.Locale(), "generate.invalid_cosmetics", err),
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+	return b.editIcon(e, func(params *generateParams) {
+		params.Cosmetics = values
+	})
+}
+
+func (b *Bot) onIconShiny(_ discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+	return b.editIcon(e, func(params *generateParams) {
+		params.Shiny = !params.Shiny
+	})
+}
+
+// onIconReorder moves the first Pokémon to the end, so every order can be reached by clicking repeatedly.
+func (b *Bot) onIconReorder(_ discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+	return b.editIcon(e, func(params *generateParams) {
+		if len(params.Pokemon) > 1 {
+			params.Pokemon = append(slices.Clone(params.Pokemon[1:]), params.Pokemon[0])
+		}
+	})
+}
+
+func (b *Bot) onIconRerender(_ discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+	return b.editIcon(e, func(*generateParams) {})
+}
+
+// editIcon generates the icon referenced by the component again with the edited parameters and replaces it in the message.
+// Only the user who generated the icon is allowed to edit it.
+func (b *Bot) editIcon(e *handler.ComponentEvent, edit func(params *generateParams)) error {
+	id, err := strconv.ParseUint(e.Vars["id"], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid icon id: %w", err)
+	}
+
+	g, err := b.db.Generation(id)
+	if err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			slog.ErrorContext(e.Ctx, "error getting generation", slog.Any("err", err))
+		}
+		return e.CreateMessage(discord.MessageCreate{
+			Content: b.translate(e.Locale(), "history.not_found", strconv.FormatUint(id, 10)),
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+	if g.UserID != e.User().ID {
+		return e.CreateMessage(discord.MessageCreate{
+			Content: b.translate(e.Locale(), "icon.not_owner"),
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+
+	params := generationParams(g)
+	edit(&params)
+
+	if err = e.DeferUpdateMessage(); err != nil {
+		return err
+	}
+	return b.generate(e.Ctx, e, params)
+}
@@ -31,6 +31,9 @@ func defaultConfig() Config {
 	return Config{
 		Repository: "https://github.com/PokeAPI/api-data",
 		ClonePath:  "data",
+		Database: DatabaseConfig{
+			Path: "pogo-icons.db",
+		},
 		Bot: BotConfig{
 			Token:        "",
 			GuildIDs:     nil,
@@ -46,20 +49,30 @@ func defaultConfig() Config {
 }
 
 type Config struct {
-	Repository string    `toml:"repository"`
-	ClonePath  string    `toml:"clone_path"`
-	Bot        BotConfig `toml:"bot"`
-	Log        LogConfig `toml:"log"`
+	Repository string         `toml:"repository"`
+	ClonePath  string         `toml:"clone_path"`
+	Database   DatabaseConfig `toml:"database"`
+	Bot        BotConfig      `toml:"bot"`
+	Log        LogConfig      `toml:"log"`
 }
 
 func (c Config) String() string {
-	return fmt.Sprintf("Repository: %s\nBot: %s\nLog: %s",
+	return fmt.Sprintf("Repository: %s\nDatabase: %s\nBot: %s\nLog: %s",
 		c.Repository,
+		c.Database,
 		c.Bot,
 		c.Log,
 	)
 }
 
+type DatabaseConfig struct {
+	Path string `toml:"path"`
+}
+
+func (c DatabaseConfig) String() string {
+	return fmt.Sprintf("\n Path: %s", c.Path)
+}
+
 type LogFormat string
 
 const (
@@ -0,0 +1,113 @@
+package pogoicons
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+)
+
+// maxChoiceLength is the maximum length of an autocomplete choice name and value.
+const maxChoiceLength = 100
+
+// parseCosmetics parses a comma separated list of the cosmetics of the config. Names are matched case-insensitively,
+// unknown names are kept as they are so icongen can report them.
+func parseCosmetics(cfg icongen.Config, value string) []string {
+	names := splitList(value)
+	for i, name := range names {
+		if cosmetic, ok := findCosmetic(cfg.Cosmetics, name); ok {
+			names[i] = cosmetic.Name
+		}
+	}
+	return names
+}
+
+// findCosmetic returns the cosmetic with the name, ignoring case.
+func findCosmetic(cosmetics []icongen.CosmeticConfig, name string) (icongen.CosmeticConfig, bool) {
+	i := slices.IndexFunc(cosmetics, func(cosmetic icongen.CosmeticConfig) bool {
+		return strings.EqualFold(cosmetic.Name, name)
+	})
+	if i == -1 {
+		return icongen.CosmeticConfig{}, false
+	}
+	return cosmetics[i], true
+}
+
+// cosmeticTerms returns the names and slots of the cosmetics.
+func cosmeticTerms(cosmetics []icongen.CosmeticConfig) []configTerm {
+	var terms []configTerm
+	for _, cosmetic := range cosmetics {
+		terms = append(terms, configTerm{name: cosmetic.Name, term: cosmetic.Name})
+		if cosmetic.Slot != "" {
+			terms = append(terms, configTerm{name: cosmetic.Name, term: cosmetic.Slot})
+		}
+	}
+	return terms
+}
+
+// onCosmeticsAutocomplete completes the last entry of a comma separated list of cosmetics by fuzzy matching their names
+// and slots. Cosmetics which conflict with the already entered ones or are not allowed in the guild are not suggested.
+func (b *Bot) onCosmeticsAutocomplete(e *handler.AutocompleteEvent) error {
+	return b.completeCosmetics(e, b.guildConfig(e.Ctx, e.GuildID()), true)
+}
+
+// completeCosmetics completes a comma separated list of the cosmetics allowed by the config, including the assets of
+// the guild. Conflicting cosmetics are not suggested if excludeConflicts is set.
+func (b *Bot) completeCosmetics(e *handler.AutocompleteEvent, cfg database.GuildConfig, excludeConflicts bool) error {
+	iconCfg := b.iconConfig(e.Ctx, e.GuildID())
+	value := e.Data.String(e.Data.Focused().Name)
+	entries := splitList(value)
+
+	var query string
+	if len(entries) > 0 && !strings.HasSuffix(strings.TrimSpace(value), ",") {
+		query = entries[len(entries)-1]
+		entries = entries[:len(entries)-1]
+	}
+
+	var selected []icongen.CosmeticConfig
+	for _, entry := range entries {
+		if cosmetic, ok := findCosmetic(iconCfg.Cosmetics, entry); ok {
+			selected = append(selected, cosmetic)
+		}
+	}
+	names := make([]string, 0, len(selected))
+	for _, cosmetic := range selected {
+		names = append(names, cosmetic.Name)
+	}
+	prefix := strings.Join(names, ", ")
+
+	choices := make([]discord.AutocompleteChoice, 0, maxChoices)
+	if prefix != "" && query == "" {
+		choices = append(choices, discord.AutocompleteChoiceString{
+			Name:  prefix,
+			Value: prefix,
+		})
+	}
+	for _, name := range searchConfig(cosmeticTerms(iconCfg.Cosmetics), query) {
+		if len(choices) >= maxChoices {
+			break
+		}
+		cosmetic, _ := iconCfg.Cosmetic(name)
+		if !cosmeticAllowed(cfg, cosmetic.Name) || slices.ContainsFunc(selected, func(other icongen.CosmeticConfig) bool {
+			return other.Name == cosmetic.Name || (excludeConflicts && other.ConflictsWith(cosmetic))
+		}) {
+			continue
+		}
+		choice := cosmetic.Name
+		if prefix != "" {
+			choice = prefix + ", " + choice
+		}
+		if len(choice) > maxChoiceLength {
+			continue
+		}
+		choices = append(choices, discord.AutocompleteChoiceString{
+			Name:  choice,
+			Value: choice,
+		})
+	}
+	return e.AutocompleteResult(choices)
+}
@@ -0,0 +1,179 @@
+package pogoicons
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+
+	"github.com/topi314/pogo-icons/internal/icongen"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+const (
+	expressionTypeEmoji   = "emoji"
+	expressionTypeSticker = "sticker"
+
+	maxEmojiNameLength   = 32
+	maxStickerNameLength = 30
+)
+
+var expressionTypeChoices = []discord.ApplicationCommandOptionChoiceString{
+	{
+		Name:  "Emoji",
+		Value: expressionTypeEmoji,
+	},
+	{
+		Name:  "Sticker",
+		Value: expressionTypeSticker,
+	},
+}
+
+// invalidEmojiNameRegex matches the characters not allowed in emoji names.
+var invalidEmojiNameRegex = regexp.MustCompile(`[^a-z0-9_]+`)
+
+// emojiName derives an emoji name like "pikachu_community_day" from the Pokémon and event.
+func emojiName(event string, pokemon []string) string {
+	var parts []string
+	for _, p := range pokemon {
+		name, _ := pokeapi.ParsePokemonName(p)
+		parts = append(parts, name)
+	}
+	parts = append(parts, event)
+
+	name := invalidEmojiNameRegex.ReplaceAllString(strings.ToLower(strings.Join(parts, "_")), "_")
+	name = strings.Trim(name[:min(len(name), maxEmojiNameLength)], "_")
+	if len(name) < 2 {
+		return "icon"
+	}
+	return name
+}
+
+// canCreateExpressions reports whether the user is allowed to upload emojis and stickers to the guild.
+func canCreateExpressions(e *handler.CommandEvent) bool {
+	member := e.Member()
+	return e.GuildID() != nil && member != nil && member.Permissions.Has(discord.PermissionCreateGuildExpressions)
+}
+
+// onEmoji generates an icon sized for Discord's emoji or sticker limits and uploads it to the guild. Animated emojis are
+// uploaded as GIF, animated stickers as APNG.
+func (b *Bot) onEmoji(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	if !canCreateExpressions(e) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "emoji.missing_permissions"))
+	}
+	// the app permissions are only set if the bot is a member of the guild
+	if perms := e.AppPermissions(); perms == nil || !perms.Has(discord.PermissionCreateGuildExpressions) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "emoji.bot_missing_permissions"))
+	}
+
+	expressionType := expressionTypeEmoji
+	if value, ok := data.OptString("type"); ok {
+		expressionType = value
+	}
+	animated := data.Bool("animated")
+
+	params := generateParams{
+		Event:   b.parseEvent(data.String("event")),
+		Pokemon: []string{data.String("pokemon")},
+		Shiny:   data.Bool("shiny"),
+		Format:  icongen.FormatPNG,
+	}
+	if cosmetics, ok := data.OptString("cosmetics"); ok {
+		params.Cosmetics = parseCosmetics(b.iconConfig(e.Ctx, e.GuildID()), cosmetics)
+	}
+	if style, ok := data.OptString("style"); ok {
+		params.Style = pokeapi.SpriteStyle(style)
+	}
+
+	opts := icongen.Options{
+		Size:     icongen.EmojiSize,
+		MaxBytes: icongen.EmojiMaxBytes,
+	}
+	if animated {
+		params.Format = icongen.FormatGIF
+	}
+	if expressionType == expressionTypeSticker {
+		opts.Size = icongen.StickerSize
+		opts.MaxBytes = icongen.StickerMaxBytes
+		if animated {
+			params.Format = icongen.FormatAPNG
+		}
+	}
+	params = params.withDefaultStyle()
+
+	if err := e.DeferCreateMessage(false); err != nil {
+		return err
+	}
+
+	icon, err := b.renderIcon(e.Ctx, e, params, opts)
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error generating icon", slog.Any("err", err))
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "generate.error", err)),
+		})
+		return err
+	}
+
+	var content string
+	if expressionType == expressionTypeSticker {
+		content, err = b.uploadSticker(e, params, icon)
+	} else {
+		content, err = b.uploadEmoji(e, params, icon)
+	}
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error uploading expression", slog.String("type", expressionType), slog.Any("err", err))
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "emoji.error", err)),
+		})
+		return err
+	}
+
+	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+		Content: json.Ptr(content),
+	})
+	return err
+}
+
+func (b *Bot) uploadEmoji(e *handler.CommandEvent, params generateParams, icon []byte) (string, error) {
+	name := emojiName(params.Event, params.Pokemon)
+	if value, ok := e.SlashCommandInteractionData().OptString("name"); ok {
+		name = value
+	}
+
+	iconType := discord.IconTypePNG
+	if params.Format == icongen.FormatGIF {
+		iconType = discord.IconTypeGIF
+	}
+	emoji, err := e.Client().Rest.CreateEmoji(*e.GuildID(), discord.EmojiCreate{
+		Name:  name,
+		Image: *discord.NewIconRaw(iconType, icon),
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.translate(e.Locale(), "emoji.emoji_success", emoji.Mention(), emoji.Name), nil
+}
+
+func (b *Bot) uploadSticker(e *handler.CommandEvent, params generateParams, icon []byte) (string, error) {
+	names := b.localizedPokemonNames(e.Ctx, e.Locale(), params.Pokemon)
+	name := truncate(fmt.Sprintf("%s %s", strings.Join(names, " "), params.Event), maxStickerNameLength)
+	if value, ok := e.SlashCommandInteractionData().OptString("name"); ok {
+		name = value
+	}
+
+	sticker, err := e.Client().Rest.CreateSticker(*e.GuildID(), discord.StickerCreate{
+		Name: name,
+		// tags are the autocomplete suggestions for the sticker
+		Tags: strings.Join(names, ", "),
+		File: discord.NewFile("sticker."+params.Format.Extension(), "", bytes.NewReader(icon)),
+	})
+	if err != nil {
+		return "", err
+	}
+	return b.translate(e.Locale(), "emoji.sticker_success", sticker.Name), nil
+}
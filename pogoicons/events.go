@@ -0,0 +1,66 @@
+package pogoicons
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+)
+
+// parseEvent returns the name of the event matching the value case-insensitively, unknown events are returned as they
+// are so icongen can report them.
+func (b *Bot) parseEvent(value string) string {
+	for _, event := range b.iconCfg.Events {
+		if strings.EqualFold(event.Name, strings.TrimSpace(value)) {
+			return event.Name
+		}
+	}
+	return value
+}
+
+// eventTerms returns the names, categories and tags of the events.
+func eventTerms(events []icongen.EventConfig) []configTerm {
+	var terms []configTerm
+	for _, event := range events {
+		terms = append(terms, configTerm{name: event.Name, term: event.Name})
+		if event.Category != "" {
+			terms = append(terms, configTerm{name: event.Name, term: event.Category})
+		}
+		for _, tag := range event.Tags {
+			terms = append(terms, configTerm{name: event.Name, term: tag})
+		}
+	}
+	return terms
+}
+
+// onEventAutocomplete suggests the events of the config, so it can hold more than the 25 choices Discord allows and
+// changes don't need a command sync. Events which are not allowed in the guild are not suggested.
+func (b *Bot) onEventAutocomplete(e *handler.AutocompleteEvent) error {
+	return b.completeEvents(e, b.guildConfig(e.Ctx, e.GuildID()))
+}
+
+func (b *Bot) completeEvents(e *handler.AutocompleteEvent, cfg database.GuildConfig) error {
+	names := searchConfig(eventTerms(b.iconCfg.Events), e.Data.String(e.Data.Focused().Name))
+	names = slices.DeleteFunc(names, func(name string) bool {
+		return !eventAllowed(cfg, name)
+	})
+
+	choices := make([]discord.AutocompleteChoice, 0, min(len(names), maxChoices))
+	for _, name := range names[:min(len(names), maxChoices)] {
+		event, _ := b.iconCfg.Event(name)
+		label := event.Name
+		if event.Category != "" && !strings.Contains(event.Name, event.Category) {
+			label = fmt.Sprintf("%s (%s)", event.Name, event.Category)
+		}
+		choices = append(choices, discord.AutocompleteChoiceString{
+			Name:  truncate(label, maxChoiceLength),
+			Value: event.Name,
+		})
+	}
+	return e.AutocompleteResult(choices)
+}
@@ -0,0 +1,221 @@
+package pogoicons
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+
+	"github.com/topi314/pogo-icons/internal/icongen"
+)
+
+// previewCache keeps rendered previews in memory, they only depend on the assets and config which don't change at runtime.
+type previewCache struct {
+	mu     sync.Mutex
+	images map[string][]byte
+}
+
+func newPreviewCache() *previewCache {
+	return &previewCache{
+		images: make(map[string][]byte),
+	}
+}
+
+// get returns the cached preview with the key or renders and caches it.
+func (c *previewCache) get(key string, render func() (io.Reader, error)) ([]byte, error) {
+	c.mu.Lock()
+	data, ok := c.images[key]
+	c.mu.Unlock()
+	if ok {
+		return data, nil
+	}
+
+	r, err := render()
+	if err != nil {
+		return nil, err
+	}
+	if data, err = io.ReadAll(r); err != nil {
+		return nil, fmt.Errorf("failed to read preview: %w", err)
+	}
+
+	c.mu.Lock()
+	c.images[key] = data
+	c.mu.Unlock()
+	return data, nil
+}
+
+// categories returns the distinct event categories in config order.
+func (b *Bot) categories() []string {
+	var categories []string
+	for _, event := range b.iconCfg.Events {
+		if event.Category != "" && !slices.Contains(categories, event.Category) {
+			categories = append(categories, event.Category)
+		}
+	}
+	return categories
+}
+
+func (b *Bot) onCategoryAutocomplete(e *handler.AutocompleteEvent) error {
+	query := strings.ToLower(e.Data.String("category"))
+	choices := make([]discord.AutocompleteChoice, 0, maxChoices)
+	for _, category := range b.categories() {
+		if len(choices) >= maxChoices {
+			break
+		}
+		if !strings.Contains(strings.ToLower(category), query) {
+			continue
+		}
+		choices = append(choices, discord.AutocompleteChoiceString{
+			Name:  category,
+			Value: category,
+		})
+	}
+	return e.AutocompleteResult(choices)
+}
+
+func (b *Bot) onEventsGallery(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	category := data.String("category")
+	var events []string
+	for _, event := range b.iconCfg.Events {
+		if category == "" || strings.EqualFold(event.Category, category) {
+			events = append(events, event.Name)
+		}
+	}
+	if len(events) == 0 {
+		_, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "events.no_events", category)),
+		})
+		return err
+	}
+
+	sheet, err := b.previews.get("sheet:"+strings.ToLower(category), func() (io.Reader, error) {
+		return icongen.ContactSheet(e.Ctx, b.assets, b.iconCfg, events, icongen.SheetOptions{})
+	})
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error generating event gallery", slog.Any("err", err))
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "events.error", err)),
+		})
+		return err
+	}
+
+	title := b.translate(e.Locale(), "events.gallery.title")
+	if category != "" {
+		title = fmt.Sprintf("%s: %s", title, category)
+	}
+	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+		Embeds: &[]discord.Embed{
+			{
+				Title:       title,
+				Description: b.translate(e.Locale(), "events.gallery.description"),
+				Image: &discord.EmbedResource{
+					URL: "attachment://events.png",
+				},
+			},
+		},
+		Files: []*discord.File{
+			discord.NewFile("events.png", "", bytes.NewReader(sheet)),
+		},
+	})
+	return err
+}
+
+func (b *Bot) onEventsCosmetics(_ discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	return b.showCosmeticPage(e.Ctx, e, 0)
+}
+
+func (b *Bot) onEventsCosmeticsPage(_ discord.ButtonInteractionData, e *handler.ComponentEvent) error {
+	page, err := strconv.Atoi(e.Vars["page"])
+	if err != nil {
+		return fmt.Errorf("invalid page: %w", err)
+	}
+	if err = e.DeferUpdateMessage(); err != nil {
+		return err
+	}
+	return b.showCosmeticPage(e.Ctx, e, page)
+}
+
+// showCosmeticPage responds to the deferred interaction with a preview of the cosmetic on the page and buttons to
+// page through all cosmetics.
+func (b *Bot) showCosmeticPage(ctx context.Context, e deferredEvent, page int) error {
+	cosmetics := b.iconCfg.Cosmetics
+	if len(cosmetics) == 0 {
+		_, err := e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "events.cosmetics.empty")),
+		})
+		return err
+	}
+	page = min(max(page, 0), len(cosmetics)-1)
+	cosmetic := cosmetics[page]
+
+	preview, err := b.previews.get("cosmetic:"+cosmetic.Name, func() (io.Reader, error) {
+		return icongen.Preview(ctx, b.assets, b.iconCfg, "", []string{cosmetic.Name})
+	})
+	if err != nil {
+		slog.ErrorContext(ctx, "error generating cosmetic preview", slog.Any("err", err))
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "events.error", err)),
+		})
+		return err
+	}
+
+	var fields []discord.EmbedField
+	if cosmetic.Slot != "" {
+		fields = append(fields, discord.EmbedField{
+			Name:   b.translate(e.Locale(), "events.cosmetics.slot"),
+			Value:  cosmetic.Slot,
+			Inline: json.Ptr(true),
+		})
+	}
+	var conflicts []string
+	for _, other := range cosmetics {
+		if other.Name != cosmetic.Name && cosmetic.ConflictsWith(other) {
+			conflicts = append(conflicts, other.Name)
+		}
+	}
+	if len(conflicts) > 0 {
+		fields = append(fields, discord.EmbedField{
+			Name:   b.translate(e.Locale(), "events.cosmetics.conflicts"),
+			Value:  truncate(strings.Join(conflicts, ", "), 1024),
+			Inline: json.Ptr(true),
+		})
+	}
+
+	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+		Embeds: &[]discord.Embed{
+			{
+				Title:  cosmetic.Name,
+				Fields: fields,
+				Image: &discord.EmbedResource{
+					URL: "attachment://cosmetic.png",
+				},
+				Footer: &discord.EmbedFooter{
+					Text: b.translate(e.Locale(), "events.cosmetics.page", page+1, len(cosmetics)),
+				},
+			},
+		},
+		Components: &[]discord.LayoutComponent{
+			discord.NewActionRow(
+				discord.NewSecondaryButton(b.translate(e.Locale(), "events.cosmetics.previous"), fmt.Sprintf("/events/cosmetics/%d", page-1)).
+					WithDisabled(page == 0),
+				discord.NewSecondaryButton(b.translate(e.Locale(), "events.cosmetics.next"), fmt.Sprintf("/events/cosmetics/%d", page+1)).
+					WithDisabled(page == len(cosmetics)-1),
+			),
+		},
+		// replace the preview of the previous page
+		Attachments: &[]discord.AttachmentUpdate{},
+		Files: []*discord.File{
+			discord.NewFile("cosmetic.png", "", bytes.NewReader(preview)),
+		},
+	})
+	return err
+}
@@ -0,0 +1,325 @@
+package pogoicons
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"testing/fstest"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+)
+
+const (
+	// brandingImage is the asset path of the guild icon drawn as branding.
+	brandingImage = "guild/icon.png"
+	// brandingScale is the height of the branding relative to the icon.
+	brandingScale = 0.2
+
+	// minWidth and maxWidth limit the width icons can be scaled down to.
+	minWidth = 128
+	maxWidth = 1024
+)
+
+var brandingChoices = []discord.ApplicationCommandOptionChoiceString{
+	{
+		Name:  "Top left",
+		Value: string(icongen.PositionTopLeft),
+	},
+	{
+		Name:  "Top right",
+		Value: string(icongen.PositionTopRight),
+	},
+	{
+		Name:  "Bottom left",
+		Value: string(icongen.PositionBottomLeft),
+	},
+	{
+		Name:  "Bottom right",
+		Value: string(icongen.PositionBottomRight),
+	},
+}
+
+// Settings which can be reset with /config reset.
+const (
+	settingAll              = "all"
+	settingDefaultEvent     = "default_event"
+	settingAllowedEvents    = "allowed_events"
+	settingAllowedCosmetics = "allowed_cosmetics"
+	settingBranding         = "branding"
+	settingWidth            = "width"
+)
+
+var settingChoices = []discord.ApplicationCommandOptionChoiceString{
+	{
+		Name:  "All",
+		Value: settingAll,
+	},
+	{
+		Name:  "Default event",
+		Value: settingDefaultEvent,
+	},
+	{
+		Name:  "Allowed events",
+		Value: settingAllowedEvents,
+	},
+	{
+		Name:  "Allowed cosmetics",
+		Value: settingAllowedCosmetics,
+	},
+	{
+		Name:  "Branding",
+		Value: settingBranding,
+	},
+	{
+		Name:  "Width",
+		Value: settingWidth,
+	},
+}
+
+// guildConfig returns the config of the guild, or the default settings outside of guilds.
+func (b *Bot) guildConfig(ctx context.Context, guildID *snowflake.ID) database.GuildConfig {
+	if guildID == nil {
+		return database.GuildConfig{}
+	}
+	cfg, err := b.db.GuildConfig(*guildID)
+	if err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			slog.ErrorContext(ctx, "error getting guild config", slog.Any("err", err))
+		}
+		return database.GuildConfig{GuildID: *guildID}
+	}
+	return cfg
+}
+
+func eventAllowed(cfg database.GuildConfig, event string) bool {
+	return len(cfg.AllowedEvents) == 0 || slices.Contains(cfg.AllowedEvents, event)
+}
+
+func cosmeticAllowed(cfg database.GuildConfig, cosmetic string) bool {
+	return len(cfg.AllowedCosmetics) == 0 || slices.Contains(cfg.AllowedCosmetics, cosmetic)
+}
+
+// checkGuildConfig returns an error if the parameters use an event or cosmetic which is not allowed in the guild.
+func checkGuildConfig(cfg database.GuildConfig, params generateParams) error {
+	if !eventAllowed(cfg, params.Event) {
+		return fmt.Errorf("event %q is not allowed in this server", params.Event)
+	}
+	for _, cosmetic := range params.Cosmetics {
+		if !cosmeticAllowed(cfg, cosmetic) {
+			return fmt.Errorf("cosmetic %q is not allowed in this server", cosmetic)
+		}
+	}
+	return nil
+}
+
+// applyGuildConfig checks the parameters against the config of the guild and adds its branding and width to the
+// options. The returned assets contain the assets of the guild and the branding image.
+func (b *Bot) applyGuildConfig(ctx context.Context, guildID *snowflake.ID, params generateParams, opts icongen.Options) (fs.FS, icongen.Options, error) {
+	cfg := b.guildConfig(ctx, guildID)
+	if err := checkGuildConfig(cfg, params); err != nil {
+		return nil, opts, err
+	}
+	assets := b.guildAssets(guildID)
+	if opts.Size == 0 && opts.Width == 0 {
+		opts.Width = cfg.Width
+	}
+	if cfg.BrandingPosition == "" {
+		return assets, opts, nil
+	}
+
+	icon, err := b.guildIcon(ctx, cfg.GuildID)
+	if err != nil {
+		// generate the icon without branding instead of failing
+		slog.WarnContext(ctx, "error getting guild icon for branding", slog.Any("err", err))
+		return assets, opts, nil
+	}
+	opts.Layers = append(opts.Layers, icongen.Layer{
+		Image:    brandingImage,
+		ScaleY:   brandingScale,
+		Position: icongen.Position(cfg.BrandingPosition),
+	})
+	return layeredFS{
+		fstest.MapFS{
+			brandingImage: &fstest.MapFile{Data: icon},
+		},
+		assets,
+	}, opts, nil
+}
+
+// guildIcon returns the icon of the guild as PNG. Icons are cached by their hash, so changed icons are downloaded again.
+func (b *Bot) guildIcon(ctx context.Context, guildID snowflake.ID) ([]byte, error) {
+	guild, ok := b.client.Caches.Guild(guildID)
+	if !ok {
+		restGuild, err := b.client.Rest.GetGuild(guildID, false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get guild: %w", err)
+		}
+		guild = restGuild.Guild
+	}
+	url := guild.IconURL(discord.WithFormat(discord.FileFormatPNG), discord.WithSize(256))
+	if url == nil {
+		return nil, errors.New("guild has no icon")
+	}
+
+	return b.previews.get("guild-icon:"+*guild.Icon, func() (io.Reader, error) {
+		rq, err := http.NewRequestWithContext(ctx, http.MethodGet, *url, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+		rs, err := http.DefaultClient.Do(rq)
+		if err != nil {
+			return nil, fmt.Errorf("failed to download guild icon: %w", err)
+		}
+		defer rs.Body.Close()
+		if rs.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("failed to download guild icon: %s", rs.Status)
+		}
+		data, err := io.ReadAll(rs.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read guild icon: %w", err)
+		}
+		return bytes.NewReader(data), nil
+	})
+}
+
+func (b *Bot) onConfigAutocomplete(e *handler.AutocompleteEvent) error {
+	switch e.Data.Focused().Name {
+	case "default_event":
+		// only suggest the events allowed by the current config
+		return b.onEventAutocomplete(e)
+	case "allowed_cosmetics":
+		return b.completeCosmetics(e, database.GuildConfig{}, false)
+	}
+	return e.AutocompleteResult([]discord.AutocompleteChoice{})
+}
+
+func (b *Bot) onConfigShow(_ discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	cfg := b.guildConfig(e.Ctx, e.GuildID())
+
+	all := b.translate(e.Locale(), "config.all")
+	none := b.translate(e.Locale(), "config.none")
+	value := func(v string, fallback string) string {
+		if v == "" {
+			return fallback
+		}
+		return truncate(v, 1024)
+	}
+	width := b.translate(e.Locale(), "config.full_size")
+	if cfg.Width > 0 {
+		width = strconv.Itoa(cfg.Width) + "px"
+	}
+
+	return e.CreateMessage(discord.MessageCreate{
+		Embeds: []discord.Embed{
+			{
+				Title: b.translate(e.Locale(), "config.title"),
+				Fields: []discord.EmbedField{
+					{Name: b.translate(e.Locale(), "config.default_event"), Value: value(cfg.DefaultEvent, none)},
+					{Name: b.translate(e.Locale(), "config.allowed_events"), Value: value(strings.Join(cfg.AllowedEvents, ", "), all)},
+					{Name: b.translate(e.Locale(), "config.allowed_cosmetics"), Value: value(strings.Join(cfg.AllowedCosmetics, ", "), all)},
+					{Name: b.translate(e.Locale(), "config.branding"), Value: value(cfg.BrandingPosition, none)},
+					{Name: b.translate(e.Locale(), "config.width"), Value: width},
+				},
+			},
+		},
+		Flags: discord.MessageFlagEphemeral,
+	})
+}
+
+func (b *Bot) onConfigSet(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	if !canManageGuild(e) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "config.missing_permissions"))
+	}
+	cfg := b.guildConfig(e.Ctx, e.GuildID())
+
+	if value, ok := data.OptString("allowed_events"); ok {
+		events := splitList(value)
+		for i, name := range events {
+			event, ok := b.iconCfg.Event(b.parseEvent(name))
+			if !ok {
+				return b.replyEphemeral(e, b.translate(e.Locale(), "config.unknown_event", name))
+			}
+			events[i] = event.Name
+		}
+		cfg.AllowedEvents = events
+	}
+	if value, ok := data.OptString("allowed_cosmetics"); ok {
+		iconCfg := b.iconConfig(e.Ctx, e.GuildID())
+		cosmetics := parseCosmetics(iconCfg, value)
+		for _, name := range cosmetics {
+			if _, ok = iconCfg.Cosmetic(name); !ok {
+				return b.replyEphemeral(e, b.translate(e.Locale(), "config.unknown_cosmetic", name))
+			}
+		}
+		cfg.AllowedCosmetics = cosmetics
+	}
+	if value, ok := data.OptString("default_event"); ok {
+		event, ok := b.iconCfg.Event(b.parseEvent(value))
+		if !ok {
+			return b.replyEphemeral(e, b.translate(e.Locale(), "config.unknown_event", value))
+		}
+		cfg.DefaultEvent = event.Name
+	}
+	if cfg.DefaultEvent != "" && !eventAllowed(cfg, cfg.DefaultEvent) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "config.default_event_not_allowed", cfg.DefaultEvent))
+	}
+	if value, ok := data.OptString("branding"); ok {
+		cfg.BrandingPosition = value
+	}
+	if value, ok := data.OptInt("width"); ok {
+		cfg.Width = value
+	}
+
+	return b.saveGuildConfig(e, cfg, "config.saved")
+}
+
+func (b *Bot) onConfigReset(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	if !canManageGuild(e) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "config.missing_permissions"))
+	}
+	cfg := b.guildConfig(e.Ctx, e.GuildID())
+	switch data.String("setting") {
+	case settingDefaultEvent:
+		cfg.DefaultEvent = ""
+	case settingAllowedEvents:
+		cfg.AllowedEvents = nil
+	case settingAllowedCosmetics:
+		cfg.AllowedCosmetics = nil
+	case settingBranding:
+		cfg.BrandingPosition = ""
+	case settingWidth:
+		cfg.Width = 0
+	default:
+		if err := b.db.DeleteGuildConfig(cfg.GuildID); err != nil {
+			slog.ErrorContext(e.Ctx, "error deleting guild config", slog.Any("err", err))
+			return b.replyEphemeral(e, b.translate(e.Locale(), "config.error", err))
+		}
+		return b.replyEphemeral(e, b.translate(e.Locale(), "config.reset"))
+	}
+	return b.saveGuildConfig(e, cfg, "config.reset")
+}
+
+// saveGuildConfig saves the config and replies with the translation of the key.
+func (b *Bot) saveGuildConfig(e *handler.CommandEvent, cfg database.GuildConfig, key string) error {
+	cfg.UpdatedBy = e.User().ID
+	cfg.UpdatedAt = time.Now()
+	if err := b.db.SaveGuildConfig(cfg); err != nil {
+		slog.ErrorContext(e.Ctx, "error saving guild config", slog.Any("err", err))
+		return b.replyEphemeral(e, b.translate(e.Locale(), "config.error", err))
+	}
+	return b.replyEphemeral(e, b.translate(e.Locale(), key))
+}
@@ -0,0 +1,170 @@
+package pogoicons
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+// historyLimit is the number of generations shown in the history.
+const historyLimit = 10
+
+// addHistory stores the generated icon in the history of the user and returns its ID. Errors are only logged as the
+// icon was generated anyway, the returned ID is 0 in that case.
+func (b *Bot) addHistory(ctx context.Context, e deferredEvent, params generateParams, icon []byte) uint64 {
+	hash := sha256.Sum256(icon)
+	g := database.Generation{
+		UserID:    e.User().ID,
+		Event:     params.Event,
+		Pokemon:   params.Pokemon,
+		Cosmetics: params.Cosmetics,
+		Format:    string(params.Format),
+		Style:     string(params.Style),
+		Shiny:     params.Shiny,
+		Hash:      hex.EncodeToString(hash[:]),
+		CreatedAt: time.Now(),
+	}
+	if guildID := e.GuildID(); guildID != nil {
+		g.GuildID = *guildID
+	}
+	g, err := b.db.AddGeneration(g)
+	if err != nil {
+		slog.ErrorContext(ctx, "error adding generation to history", slog.Any("err", err))
+		return 0
+	}
+	return g.ID
+}
+
+// generationParams returns the generate parameters of the generation.
+func generationParams(g database.Generation) generateParams {
+	return generateParams{
+		Event:     g.Event,
+		Pokemon:   g.Pokemon,
+		Cosmetics: g.Cosmetics,
+		Format:    icongen.Format(g.Format),
+		Style:     pokeapi.SpriteStyle(g.Style),
+		Shiny:     g.Shiny,
+	}
+}
+
+func (b *Bot) onHistoryList(_ discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	generations, err := b.db.UserGenerations(e.User().ID, historyLimit)
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error getting history", slog.Any("err", err))
+		return e.CreateMessage(discord.MessageCreate{
+			Content: b.translate(e.Locale(), "history.error", err),
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+	if len(generations) == 0 {
+		return e.CreateMessage(discord.MessageCreate{
+			Content: b.translate(e.Locale(), "history.empty"),
+			Flags:   discord.MessageFlagEphemeral,
+		})
+	}
+
+	lines := make([]string, 0, len(generations))
+	for _, g := range generations {
+		names := b.localizedPokemonNames(e.Ctx, e.Locale(), g.Pokemon)
+		line := fmt.Sprintf("`#%d` %s **%s** %s", g.ID, discord.FormattedTimestampMention(g.CreatedAt.Unix(), discord.TimestampStyleRelative), g.Event, strings.Join(names, ", "))
+		if len(g.Cosmetics) > 0 {
+			line += fmt.Sprintf(" + %s", strings.Join(g.Cosmetics, ", "))
+		}
+		lines = append(lines, fmt.Sprintf("%s `%s`", line, g.Format))
+	}
+
+	return e.CreateMessage(discord.MessageCreate{
+		Embeds: []discord.Embed{
+			{
+				Title:       b.translate(e.Locale(), "history.title"),
+				Description: strings.Join(lines, "\n"),
+				Footer: &discord.EmbedFooter{
+					Text: b.translate(e.Locale(), "history.footer"),
+				},
+			},
+		},
+		Flags: discord.MessageFlagEphemeral,
+	})
+}
+
+func (b *Bot) onHistoryRerunAutocomplete(e *handler.AutocompleteEvent) error {
+	switch e.Data.Focused().Name {
+	case "event":
+		return b.onEventAutocomplete(e)
+	case "cosmetics":
+		return b.onCosmeticsAutocomplete(e)
+	}
+
+	generations, err := b.db.UserGenerations(e.User().ID, maxChoices)
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error getting history", slog.Any("err", err))
+		return e.AutocompleteResult([]discord.AutocompleteChoice{})
+	}
+
+	query := strings.ToLower(e.Data.String("icon"))
+	choices := make([]discord.AutocompleteChoice, 0, len(generations))
+	for _, g := range generations {
+		name := fmt.Sprintf("#%d %s: %s", g.ID, g.Event, strings.Join(b.localizedPokemonNames(e.Ctx, e.Locale(), g.Pokemon), ", "))
+		if query != "" && !strings.Contains(strings.ToLower(name), query) {
+			continue
+		}
+		choices = append(choices, discord.AutocompleteChoiceInt{
+			Name:  truncate(name, 100),
+			Value: int(g.ID),
+		})
+	}
+	return e.AutocompleteResult(choices)
+}
+
+func (b *Bot) onHistoryRerun(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	id := data.Int("icon")
+	g, err := b.db.Generation(uint64(id))
+	if err != nil || g.UserID != e.User().ID {
+		if err != nil && !errors.Is(err, database.ErrNotFound) {
+			slog.ErrorContext(e.Ctx, "error getting generation", slog.Any("err", err))
+		}
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "history.not_found", strconv.Itoa(id))),
+		})
+		return err
+	}
+
+	params := generationParams(g)
+	if event, ok := data.OptString("event"); ok {
+		params.Event = b.parseEvent(event)
+	}
+	if cosmetics, ok := data.OptString("cosmetics"); ok {
+		params.Cosmetics = parseCosmetics(b.iconConfig(e.Ctx, e.GuildID()), cosmetics)
+	}
+	if format, ok := data.OptString("format"); ok {
+		params.Format = icongen.Format(format)
+	}
+	if style, ok := data.OptString("style"); ok {
+		params.Style = pokeapi.SpriteStyle(style)
+	}
+
+	return b.generate(e.Ctx, e, params)
+}
+
+// truncate shortens s to at most n runes.
+func truncate(s string, n int) string {
+	runes := []rune(s)
+	if len(runes) <= n {
+		return s
+	}
+	return string(runes[:n-1]) + "…"
+}
@@ -0,0 +1,71 @@
+package pogoicons
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/disgoorg/disgo/discord"
+
+	"github.com/topi314/pogo-icons/internal/i18n"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+// languages maps Discord locales to PokéAPI languages. Locales without Pokémon names fall back to English.
+var languages = map[discord.Locale]string{
+	discord.LocaleGerman:       "de",
+	discord.LocaleFrench:       "fr",
+	discord.LocaleSpanishES:    "es",
+	discord.LocaleSpanishLATAM: "es",
+	discord.LocaleItalian:      "it",
+	discord.LocaleJapanese:     "ja",
+	discord.LocaleKorean:       "ko",
+	discord.LocaleChineseCN:    "zh-Hans",
+	discord.LocaleChineseTW:    "zh-Hant",
+}
+
+func language(locale discord.Locale) string {
+	if l, ok := languages[locale]; ok {
+		return l
+	}
+	return "en"
+}
+
+// localizedPokemonNames returns the names of the Pokémon in the given locale. Pokémon which can't be found keep their value.
+func (b *Bot) localizedPokemonNames(ctx context.Context, locale discord.Locale, pokemon []string) []string {
+	lang := language(locale)
+	names := make([]string, 0, len(pokemon))
+	for _, p := range pokemon {
+		value, female := pokeapi.ParsePokemonName(p)
+		pf, err := b.pokeClient.GetPokemonForm(ctx, value)
+		if err != nil {
+			slog.DebugContext(ctx, "failed to get pokemon name", slog.String("pokemon", p), slog.Any("err", err))
+			names = append(names, p)
+			continue
+		}
+		name := pf.LocalizedName(lang)
+		if female {
+			name += " ♀"
+		}
+		names = append(names, name)
+	}
+	return names
+}
+
+// translate returns the message of the key in the locale.
+func (b *Bot) translate(locale discord.Locale, key string, args ...any) string {
+	return b.translator.Translate(locale.Code(), key, args...)
+}
+
+// localizations returns the message of the key in all Discord locales which have a translation.
+func (b *Bot) localizations(key string) map[discord.Locale]string {
+	localizations := make(map[discord.Locale]string)
+	for locale := range discord.Locales {
+		if locale == discord.LocaleUnknown || locale == i18n.DefaultLocale {
+			continue
+		}
+		if msg, ok := b.translator.Lookup(locale.Code(), key); ok {
+			localizations[locale] = msg
+		}
+	}
+	return localizations
+}
@@ -0,0 +1,257 @@
+package pogoicons
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+// presetValue returns the autocomplete value of a preset, the scope is needed as users and guilds can have presets with the same name.
+func presetValue(p database.Preset) string {
+	return string(p.Scope) + ":" + p.Name
+}
+
+// findPreset returns the preset by its autocomplete value. Values without a known scope prefix are looked up as plain
+// names in the user presets first and then in the guild presets, names may contain ":" themselves.
+func (b *Bot) findPreset(userID snowflake.ID, guildID *snowflake.ID, value string) (database.Preset, error) {
+	if scope, name, ok := strings.Cut(value, ":"); ok {
+		switch database.PresetScope(scope) {
+		case database.PresetScopeUser:
+			return b.db.Preset(userID, name)
+		case database.PresetScopeGuild:
+			if guildID == nil {
+				return database.Preset{}, database.ErrNotFound
+			}
+			return b.db.Preset(*guildID, name)
+		}
+	}
+
+	p, err := b.db.Preset(userID, value)
+	if errors.Is(err, database.ErrNotFound) && guildID != nil {
+		return b.db.Preset(*guildID, value)
+	}
+	return p, err
+}
+
+// presetParams returns the generate parameters of the preset.
+func presetParams(p database.Preset) generateParams {
+	return generateParams{
+		Event:     p.Event,
+		Pokemon:   p.Pokemon,
+		Cosmetics: p.Cosmetics,
+		Format:    icongen.Format(p.Format),
+		Style:     pokeapi.SpriteStyle(p.Style),
+		Shiny:     p.Shiny,
+	}
+}
+
+// canManageGuild returns whether the member is allowed to save and delete presets of the guild.
+func canManageGuild(e *handler.CommandEvent) bool {
+	member := e.Member()
+	return e.GuildID() != nil && member != nil && member.Permissions.Has(discord.PermissionManageGuild)
+}
+
+func (b *Bot) onPresetAutocomplete(e *handler.AutocompleteEvent) error {
+	presets, err := b.db.Presets(e.User().ID)
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error getting presets", slog.Any("err", err))
+		return e.AutocompleteResult([]discord.AutocompleteChoice{})
+	}
+	if guildID := e.GuildID(); guildID != nil {
+		guildPresets, err := b.db.Presets(*guildID)
+		if err != nil {
+			slog.ErrorContext(e.Ctx, "error getting guild presets", slog.Any("err", err))
+			return e.AutocompleteResult([]discord.AutocompleteChoice{})
+		}
+		presets = append(presets, guildPresets...)
+	}
+
+	query := strings.ToLower(e.Data.String(e.Data.Focused().Name))
+	choices := make([]discord.AutocompleteChoice, 0, min(len(presets), maxChoices))
+	for _, p := range presets {
+		if len(choices) >= maxChoices {
+			break
+		}
+		if !strings.Contains(strings.ToLower(p.Name), query) {
+			continue
+		}
+		choices = append(choices, discord.AutocompleteChoiceString{
+			Name:  truncate(fmt.Sprintf("%s (%s)", p.Name, b.translate(e.Locale(), "preset.scope."+string(p.Scope))), 100),
+			Value: presetValue(p),
+		})
+	}
+	return e.AutocompleteResult(choices)
+}
+
+func (b *Bot) onPresetSave(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	preset := database.Preset{
+		Name:      data.String("name"),
+		Scope:     database.PresetScopeUser,
+		OwnerID:   e.User().ID,
+		CreatedBy: e.User().ID,
+		UpdatedAt: time.Now(),
+	}
+	if scope, ok := data.OptString("scope"); ok {
+		preset.Scope = database.PresetScope(scope)
+	}
+	if preset.Scope == database.PresetScopeGuild {
+		if !canManageGuild(e) {
+			return b.replyEphemeral(e, b.translate(e.Locale(), "preset.missing_permissions"))
+		}
+		preset.OwnerID = *e.GuildID()
+	}
+
+	if id, ok := data.OptInt("icon"); ok {
+		g, err := b.db.Generation(uint64(id))
+		if err != nil || g.UserID != e.User().ID {
+			return b.replyEphemeral(e, b.translate(e.Locale(), "history.not_found", fmt.Sprint(id)))
+		}
+		preset.Event = g.Event
+		preset.Pokemon = g.Pokemon
+		preset.Cosmetics = g.Cosmetics
+		preset.Format = g.Format
+		preset.Style = g.Style
+		preset.Shiny = g.Shiny
+	}
+	if event, ok := data.OptString("event"); ok {
+		preset.Event = b.parseEvent(event)
+	}
+	if pokemon, ok := data.OptString("pokemon"); ok {
+		preset.Pokemon = splitList(pokemon)
+	}
+	if cosmetics, ok := data.OptString("cosmetics"); ok {
+		preset.Cosmetics = parseCosmetics(b.iconConfig(e.Ctx, e.GuildID()), cosmetics)
+	}
+	if format, ok := data.OptString("format"); ok {
+		preset.Format = format
+	}
+	if style, ok := data.OptString("style"); ok {
+		preset.Style = style
+	}
+	if shiny, ok := data.OptBool("shiny"); ok {
+		preset.Shiny = shiny
+	}
+
+	iconCfg := b.iconConfig(e.Ctx, e.GuildID())
+	if _, ok := iconCfg.Event(preset.Event); !ok {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "preset.missing_event"))
+	}
+	if err := iconCfg.CheckCosmetics(preset.Cosmetics); err != nil {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "generate.invalid_cosmetics", err))
+	}
+
+	if err := b.db.SavePreset(preset); err != nil {
+		slog.ErrorContext(e.Ctx, "error saving preset", slog.Any("err", err))
+		return b.replyEphemeral(e, b.translate(e.Locale(), "preset.error", err))
+	}
+	return b.replyEphemeral(e, b.translate(e.Locale(), "preset.saved", preset.Name))
+}
+
+func (b *Bot) onPresetLoad(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	name := data.String("name")
+	preset, err := b.findPreset(e.User().ID, e.GuildID(), name)
+	if err != nil {
+		if !errors.Is(err, database.ErrNotFound) {
+			slog.ErrorContext(e.Ctx, "error getting preset", slog.Any("err", err))
+		}
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "preset.not_found", name)),
+		})
+		return err
+	}
+
+	return b.generate(e.Ctx, e, presetParams(preset))
+}
+
+func (b *Bot) onPresetList(_ discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	userPresets, err := b.db.Presets(e.User().ID)
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error getting presets", slog.Any("err", err))
+		return b.replyEphemeral(e, b.translate(e.Locale(), "preset.error", err))
+	}
+	var guildPresets []database.Preset
+	if guildID := e.GuildID(); guildID != nil {
+		if guildPresets, err = b.db.Presets(*guildID); err != nil {
+			slog.ErrorContext(e.Ctx, "error getting guild presets", slog.Any("err", err))
+			return b.replyEphemeral(e, b.translate(e.Locale(), "preset.error", err))
+		}
+	}
+	if len(userPresets) == 0 && len(guildPresets) == 0 {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "preset.empty"))
+	}
+
+	var fields []discord.EmbedField
+	for _, presets := range [][]database.Preset{userPresets, guildPresets} {
+		if len(presets) == 0 {
+			continue
+		}
+		lines := make([]string, 0, len(presets))
+		for _, p := range presets {
+			line := fmt.Sprintf("**%s**: %s", p.Name, p.Event)
+			if len(p.Pokemon) > 0 {
+				line += " " + strings.Join(b.localizedPokemonNames(e.Ctx, e.Locale(), p.Pokemon), ", ")
+			}
+			lines = append(lines, line)
+		}
+		fields = append(fields, discord.EmbedField{
+			Name:  b.translate(e.Locale(), "preset.list."+string(presets[0].Scope)),
+			Value: truncate(strings.Join(lines, "\n"), 1024),
+		})
+	}
+
+	return e.CreateMessage(discord.MessageCreate{
+		Embeds: []discord.Embed{
+			{
+				Title:  b.translate(e.Locale(), "preset.list.title"),
+				Fields: fields,
+			},
+		},
+		Flags: discord.MessageFlagEphemeral,
+	})
+}
+
+func (b *Bot) onPresetDelete(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	name := data.String("name")
+	preset, err := b.findPreset(e.User().ID, e.GuildID(), name)
+	if err != nil {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "preset.not_found", name))
+	}
+	if preset.Scope == database.PresetScopeGuild && !canManageGuild(e) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "preset.missing_permissions"))
+	}
+
+	if err = b.db.DeletePreset(preset.OwnerID, preset.Name); err != nil {
+		slog.ErrorContext(e.Ctx, "error deleting preset", slog.Any("err", err))
+		return b.replyEphemeral(e, b.translate(e.Locale(), "preset.error", err))
+	}
+	return b.replyEphemeral(e, b.translate(e.Locale(), "preset.deleted", preset.Name))
+}
+
+func (b *Bot) replyEphemeral(e *handler.CommandEvent, content string) error {
+	return e.CreateMessage(discord.MessageCreate{
+		Content: content,
+		Flags:   discord.MessageFlagEphemeral,
+	})
+}
+
+// splitList splits a comma separated list and drops empty entries.
+func splitList(s string) []string {
+	var values []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
@@ -0,0 +1,67 @@
+package pogoicons
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/topi314/pogo-icons/internal/database"
+)
+
+func TestFindPreset(t *testing.T) {
+	db, err := database.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = db.Close()
+	})
+
+	userID := snowflake.ID(1)
+	guildID := snowflake.ID(2)
+	for _, p := range []database.Preset{
+		{Name: "raid:hour", Scope: database.PresetScopeUser, OwnerID: userID, Event: "user raid hour"},
+		{Name: "hour", Scope: database.PresetScopeUser, OwnerID: userID, Event: "user hour"},
+		{Name: "raid:hour", Scope: database.PresetScopeGuild, OwnerID: guildID, Event: "guild raid hour"},
+		{Name: "spotlight", Scope: database.PresetScopeGuild, OwnerID: guildID, Event: "guild spotlight"},
+	} {
+		if err = db.SavePreset(p); err != nil {
+			t.Fatalf("failed to save preset: %v", err)
+		}
+	}
+
+	b := &Bot{db: db}
+	tests := []struct {
+		name    string
+		guildID *snowflake.ID
+		value   string
+		want    string
+	}{
+		{name: "plain name", guildID: &guildID, value: "hour", want: "user hour"},
+		{name: "plain name with colon", guildID: &guildID, value: "raid:hour", want: "user raid hour"},
+		{name: "user scope with colon", guildID: &guildID, value: "user:raid:hour", want: "user raid hour"},
+		{name: "guild scope with colon", guildID: &guildID, value: "guild:raid:hour", want: "guild raid hour"},
+		{name: "guild fallback", guildID: &guildID, value: "spotlight", want: "guild spotlight"},
+		{name: "guild scope outside guild", value: "guild:spotlight"},
+		{name: "unknown", guildID: &guildID, value: "raid:spotlight"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := b.findPreset(userID, tt.guildID, tt.value)
+			if tt.want == "" {
+				if !errors.Is(err, database.ErrNotFound) {
+					t.Fatalf("expected not found, got %+v, %v", p, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("failed to find preset: %v", err)
+			}
+			if p.Event != tt.want {
+				t.Fatalf("got preset %q, want %q", p.Event, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+package pogoicons
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+// Keys of the extra metadata the bot embeds into generated icons.
+const (
+	metadataStyle = "style"
+	metadataShiny = "shiny"
+)
+
+const (
+	// remixDownloadTimeout limits reading the metadata of an attachment, the modal has to be opened within 3 seconds.
+	remixDownloadTimeout = 2 * time.Second
+	// maxRemixAttachmentBytes is the maximum size of attachments whose metadata is read.
+	maxRemixAttachmentBytes = 8 * 1024 * 1024
+)
+
+// remixParams returns the generate parameters of an icon message. Icons with components of the bot are looked up in
+// the history, otherwise the metadata of the attached PNGs is read.
+func (b *Bot) remixParams(ctx context.Context, applicationID string, msg discord.Message) (generateParams, bool) {
+	if msg.Author.ID.String() == applicationID {
+		for c := range msg.AllComponents() {
+			ic, ok := c.(discord.InteractiveComponent)
+			if !ok {
+				continue
+			}
+			id, ok := strings.CutPrefix(ic.GetCustomID(), "/icon/")
+			if !ok {
+				continue
+			}
+			id, _, _ = strings.Cut(id, "/")
+			generationID, err := strconv.ParseUint(id, 10, 64)
+			if err != nil {
+				continue
+			}
+			if g, err := b.db.Generation(generationID); err == nil {
+				return generationParams(g), true
+			}
+		}
+	}
+
+	for _, attachment := range msg.Attachments {
+		if !strings.HasSuffix(strings.ToLower(attachment.Filename), ".png") || attachment.Size > maxRemixAttachmentBytes {
+			continue
+		}
+		if attachment.ContentType != nil && *attachment.ContentType != "image/png" {
+			continue
+		}
+		metadata, err := readAttachmentMetadata(ctx, attachment.URL)
+		if err != nil {
+			if !errors.Is(err, icongen.ErrNoMetadata) {
+				slog.ErrorContext(ctx, "error reading icon metadata", slog.Any("err", err))
+			}
+			continue
+		}
+		shiny, _ := strconv.ParseBool(metadata.Extra[metadataShiny])
+		return generateParams{
+			Event:     metadata.Event,
+			Pokemon:   metadata.Pokemon,
+			Cosmetics: metadata.Cosmetics,
+			Format:    metadata.Format,
+			Style:     pokeapi.SpriteStyle(metadata.Extra[metadataStyle]),
+			Shiny:     shiny,
+		}, true
+	}
+	return generateParams{}, false
+}
+
+// readAttachmentMetadata reads the icon metadata of the attachment, only the beginning of the file is downloaded.
+func readAttachmentMetadata(ctx context.Context, url string) (icongen.Metadata, error) {
+	ctx, cancel := context.WithTimeout(ctx, remixDownloadTimeout)
+	defer cancel()
+
+	rq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return icongen.Metadata{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	rs, err := http.DefaultClient.Do(rq)
+	if err != nil {
+		return icongen.Metadata{}, fmt.Errorf("failed to download attachment: %w", err)
+	}
+	defer rs.Body.Close()
+	if rs.StatusCode < 200 || rs.StatusCode >= 300 {
+		return icongen.Metadata{}, fmt.Errorf("failed to download attachment: %s", rs.Status)
+	}
+	return icongen.ReadMetadata(io.LimitReader(rs.Body, maxRemixAttachmentBytes))
+}
+
+func (b *Bot) onRemixIcon(data discord.MessageCommandInteractionData, e *handler.CommandEvent) error {
+	params, ok := b.remixParams(e.Ctx, e.ApplicationID().String(), data.TargetMessage())
+	if !ok {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "remix.no_metadata"))
+	}
+	return e.Modal(b.remixModal(e.Locale(), b.guildConfig(e.Ctx, e.GuildID()), params))
+}
+
+// remixModal returns a modal pre-filled with the parameters, only the events allowed by the config are offered. The shiny
+// option doesn't fit into the modal and is kept in the custom ID instead.
+func (b *Bot) remixModal(locale discord.Locale, cfg database.GuildConfig, params generateParams) discord.ModalCreate {
+	events := b.selectEvents(cfg, params.Event)
+	eventOptions := make([]discord.StringSelectMenuOption, 0, len(events))
+	for _, event := range events {
+		option := discord.NewStringSelectMenuOption(event.Name, event.Name)
+		option.Default = event.Name == params.Event
+		eventOptions = append(eventOptions, option)
+	}
+
+	format := params.Format
+	if format == "" {
+		format = icongen.FormatPNG
+	}
+	formatOptions := make([]discord.StringSelectMenuOption, 0, len(formatChoices))
+	for _, choice := range formatChoices {
+		option := discord.NewStringSelectMenuOption(choice.Name, choice.Value)
+		option.Default = choice.Value == string(format)
+		formatOptions = append(formatOptions, option)
+	}
+	styleOptions := make([]discord.StringSelectMenuOption, 0, len(styleChoices))
+	for _, choice := range styleChoices {
+		option := discord.NewStringSelectMenuOption(choice.Name, choice.Value)
+		option.Default = choice.Value == string(params.Style)
+		styleOptions = append(styleOptions, option)
+	}
+
+	pokemon := discord.NewShortTextInput("pokemon")
+	pokemon.Value = strings.Join(params.Pokemon, ", ")
+	cosmetics := discord.NewShortTextInput("cosmetics")
+	cosmetics.Value = strings.Join(params.Cosmetics, ", ")
+
+	return discord.ModalCreate{
+		CustomID: "/remix/" + strconv.FormatBool(params.Shiny),
+		Title:    b.translate(locale, "remix.title"),
+		Components: []discord.LayoutComponent{
+			discord.NewLabel(b.translate(locale, "remix.event"), discord.NewStringSelectMenu("event", "", eventOptions...).WithRequired(true)),
+			discord.NewLabel(b.translate(locale, "remix.pokemon"), pokemon).
+				WithDescription(b.translate(locale, "remix.pokemon_description")),
+			discord.NewLabel(b.translate(locale, "remix.cosmetics"), cosmetics),
+			discord.NewLabel(b.translate(locale, "remix.format"), discord.NewStringSelectMenu("format", "", formatOptions...).WithRequired(true)),
+			discord.NewLabel(b.translate(locale, "remix.style"), discord.NewStringSelectMenu("style", "", styleOptions...)),
+		},
+	}
+}
+
+func (b *Bot) onRemixSubmit(e *handler.ModalEvent) error {
+	if err := e.DeferCreateMessage(false); err != nil {
+		return err
+	}
+
+	shiny, _ := strconv.ParseBool(e.Vars["shiny"])
+	params := generateParams{
+		Pokemon:   splitList(e.Data.Text("pokemon")),
+		Cosmetics: parseCosmetics(b.iconConfig(e.Ctx, e.GuildID()), e.Data.Text("cosmetics")),
+		Shiny:     shiny,
+	}
+	if values := e.Data.StringValues("event"); len(values) > 0 {
+		params.Event = values[0]
+	}
+	if values := e.Data.StringValues("format"); len(values) > 0 {
+		params.Format = icongen.Format(values[0])
+	}
+	if values := e.Data.StringValues("style"); len(values) > 0 {
+		params.Style = pokeapi.SpriteStyle(values[0])
+	}
+
+	return b.generate(e.Ctx, e, params)
+}
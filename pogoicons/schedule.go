@@ -0,0 +1,159 @@
+package pogoicons
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/disgoorg/disgo/discord"
+	"github.com/disgoorg/disgo/handler"
+	"github.com/disgoorg/json"
+
+	"github.com/topi314/pogo-icons/internal/icongen"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+// scheduleTimeLayouts are the layouts accepted for the start and end time besides Discord timestamps and unix seconds.
+var scheduleTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02 15:04",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+}
+
+// discordTimestampRegex matches Discord timestamps like <t:1700000000:F>.
+var discordTimestampRegex = regexp.MustCompile(`^<t:(-?\d+)(?::[tTdDfFR])?>$`)
+
+// parseEventTime parses a time in one of the scheduleTimeLayouts in the location, a Discord timestamp or unix seconds.
+func parseEventTime(value string, loc *time.Location) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if matches := discordTimestampRegex.FindStringSubmatch(value); matches != nil {
+		value = matches[1]
+	}
+	if seconds, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+	for _, layout := range scheduleTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time: %q", value)
+}
+
+// canCreateEvents reports whether the user is allowed to create scheduled events in the guild.
+func canCreateEvents(e *handler.CommandEvent) bool {
+	member := e.Member()
+	return e.GuildID() != nil && member != nil && member.Permissions.Has(discord.PermissionCreateEvents)
+}
+
+func (b *Bot) onSchedule(data discord.SlashCommandInteractionData, e *handler.CommandEvent) error {
+	if !canCreateEvents(e) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "schedule.missing_permissions"))
+	}
+	// the app permissions are only set if the bot is a member of the guild
+	if perms := e.AppPermissions(); perms == nil || !perms.Has(discord.PermissionCreateEvents) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "schedule.bot_missing_permissions"))
+	}
+
+	loc := time.UTC
+	if timezone, ok := data.OptString("timezone"); ok {
+		var err error
+		if loc, err = time.LoadLocation(timezone); err != nil {
+			return b.replyEphemeral(e, b.translate(e.Locale(), "schedule.invalid_timezone", timezone))
+		}
+	}
+	start, err := parseEventTime(data.String("start"), loc)
+	if err != nil {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "schedule.invalid_time", data.String("start")))
+	}
+	if !start.After(time.Now()) {
+		return b.replyEphemeral(e, b.translate(e.Locale(), "schedule.past_start"))
+	}
+	var end *time.Time
+	if value, ok := data.OptString("end"); ok {
+		t, err := parseEventTime(value, loc)
+		if err != nil {
+			return b.replyEphemeral(e, b.translate(e.Locale(), "schedule.invalid_time", value))
+		}
+		if !t.After(start) {
+			return b.replyEphemeral(e, b.translate(e.Locale(), "schedule.invalid_end"))
+		}
+		end = &t
+	}
+
+	eventCreate := discord.GuildScheduledEventCreate{
+		Name:               data.String("name"),
+		Description:        data.String("description"),
+		PrivacyLevel:       discord.ScheduledEventPrivacyLevelGuildOnly,
+		ScheduledStartTime: start,
+		ScheduledEndTime:   end,
+	}
+	if channel, ok := data.OptChannel("channel"); ok {
+		eventCreate.ChannelID = channel.ID
+		eventCreate.EntityType = discord.ScheduledEventEntityTypeVoice
+		if channel.Type == discord.ChannelTypeGuildStageVoice {
+			eventCreate.EntityType = discord.ScheduledEventEntityTypeStageInstance
+		}
+	} else {
+		// external events need a location and an end time
+		location, ok := data.OptString("location")
+		if !ok || end == nil {
+			return b.replyEphemeral(e, b.translate(e.Locale(), "schedule.missing_location"))
+		}
+		eventCreate.EntityType = discord.ScheduledEventEntityTypeExternal
+		eventCreate.EntityMetaData = &discord.EntityMetaData{
+			Location: location,
+		}
+	}
+
+	params := generateParams{
+		Event: b.parseEvent(data.String("event")),
+		// event covers can't be animated
+		Format: icongen.FormatPNG,
+	}
+	for _, name := range []string{"pokemon1", "pokemon2", "pokemon3", "pokemon4", "pokemon5", "pokemon6"} {
+		if pokemon, ok := data.OptString(name); ok {
+			params.Pokemon = append(params.Pokemon, pokemon)
+		}
+	}
+	if cosmetics, ok := data.OptString("cosmetics"); ok {
+		params.Cosmetics = parseCosmetics(b.iconConfig(e.Ctx, e.GuildID()), cosmetics)
+	}
+	if style, ok := data.OptString("style"); ok {
+		params.Style = pokeapi.SpriteStyle(style)
+	}
+	params = params.withDefaultStyle()
+
+	if err = e.DeferCreateMessage(false); err != nil {
+		return err
+	}
+
+	icon, err := b.renderIcon(e.Ctx, e, params, icongen.Options{})
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error generating icon", slog.Any("err", err))
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "generate.error", err)),
+		})
+		return err
+	}
+	b.addHistory(e.Ctx, e, params, icon)
+
+	eventCreate.Image = discord.NewIconRaw(discord.IconTypePNG, icon)
+	event, err := e.Client().Rest.CreateGuildScheduledEvent(*e.GuildID(), eventCreate)
+	if err != nil {
+		slog.ErrorContext(e.Ctx, "error creating scheduled event", slog.Any("err", err))
+		_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+			Content: json.Ptr(b.translate(e.Locale(), "schedule.error", err)),
+		})
+		return err
+	}
+
+	_, err = e.UpdateInteractionResponse(discord.MessageUpdate{
+		Content: json.Ptr(b.translate(e.Locale(), "schedule.success", event.Name, fmt.Sprintf("https://discord.com/events/%s/%s", event.GuildID, event.ID))),
+	})
+	return err
+}
@@ -0,0 +1,188 @@
+package pogoicons
+
+import (
+	"cmp"
+	"slices"
+	"strings"
+
+	"go.gopad.dev/fuzzysearch/fuzzy"
+
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+const (
+	// maxChoices is the maximum number of autocomplete choices Discord accepts.
+	maxChoices = 25
+	// maxFormsPerSpecies limits how many forms of one species are suggested before other species get a chance.
+	maxFormsPerSpecies = 3
+
+	exactMatchBoost  = 50
+	prefixMatchBoost = 20
+	nameMatchBoost   = 10
+	defaultFormBoost = 10
+	userUsageBoost   = 8
+	guildUsageBoost  = 4
+)
+
+// pokemonName is a name of a Pokémon form in any language used to search for Pokémon.
+type pokemonName struct {
+	form pokeapi.PokemonForm
+	name string
+}
+
+func (n pokemonName) FilterValue() string {
+	return n.name
+}
+
+// pokemonNames returns all names of the forms in all languages.
+func pokemonNames(forms []pokeapi.PokemonForm) []pokemonName {
+	names := make([]pokemonName, 0, len(forms))
+	for _, form := range forms {
+		names = append(names, pokemonName{form: form, name: form.Name})
+		for _, name := range form.Names {
+			if name == form.Name {
+				continue
+			}
+			names = append(names, pokemonName{form: form, name: name})
+		}
+	}
+	return names
+}
+
+// configTerm is a name, category or tag of an event or cosmetic used to search for them.
+type configTerm struct {
+	name string
+	term string
+}
+
+func (t configTerm) FilterValue() string {
+	return t.term
+}
+
+// searchConfig returns the names of the events or cosmetics whose terms match the query, ranked like searchPokemon.
+// Matching the name ranks higher than matching a category or tag. Without a query all names are returned in order.
+func searchConfig(terms []configTerm, query string) []string {
+	type result struct {
+		name  string
+		score float64
+		// index is the index of the first term of the name, which keeps the order of the config
+		index int
+	}
+
+	query = strings.ToLower(query)
+	var results []result
+	indices := make(map[string]int)
+	for _, rank := range fuzzy.RankFindNormalizedFold(query, terms) {
+		term := strings.ToLower(rank.Target.term)
+		score := -float64(rank.Distance)
+		switch {
+		case query == "":
+		case term == query:
+			score += exactMatchBoost
+		case strings.HasPrefix(term, query):
+			score += prefixMatchBoost
+		}
+		if rank.Target.term == rank.Target.name {
+			score += nameMatchBoost
+		}
+
+		if i, ok := indices[rank.Target.name]; ok {
+			results[i].score = max(results[i].score, score)
+			results[i].index = min(results[i].index, rank.OriginalIndex)
+			continue
+		}
+		indices[rank.Target.name] = len(results)
+		results = append(results, result{name: rank.Target.name, score: score, index: rank.OriginalIndex})
+	}
+
+	slices.SortFunc(results, func(a result, b result) int {
+		if query == "" {
+			return cmp.Compare(a.index, b.index)
+		}
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(a.index, b.index))
+	})
+	names := make([]string, 0, len(results))
+	for _, r := range results {
+		names = append(names, r.name)
+	}
+	return names
+}
+
+type searchResult struct {
+	form  pokeapi.PokemonForm
+	score float64
+}
+
+// searchPokemon returns the forms matching the query ranked by how well they match. Structured queries like "#006" or
+// "fire gen 1" filter the forms, everything else is fuzzy matched against the names in all languages.
+// Exact and prefix matches, default forms and Pokémon for which usage returns a high score are ranked first.
+func searchPokemon(forms []pokeapi.PokemonForm, query string, usage func(form pokeapi.PokemonForm) float64) []pokeapi.PokemonForm {
+	var results []searchResult
+	if matches, ok := pokeapi.Filter(forms, query); ok {
+		results = make([]searchResult, 0, len(matches))
+		for _, form := range matches {
+			results = append(results, searchResult{form: form})
+		}
+	} else {
+		query = strings.ToLower(query)
+		ranks := fuzzy.RankFindNormalizedFold(query, pokemonNames(forms))
+		indices := make(map[string]int, len(ranks))
+		for _, rank := range ranks {
+			name := strings.ToLower(rank.Target.name)
+			score := -float64(rank.Distance)
+			switch {
+			case query == "":
+			case name == query:
+				score += exactMatchBoost
+			case strings.HasPrefix(name, query):
+				score += prefixMatchBoost
+			}
+
+			// keep the best matching name of every form
+			if i, ok := indices[rank.Target.form.Value]; ok {
+				results[i].score = max(results[i].score, score)
+				continue
+			}
+			indices[rank.Target.form.Value] = len(results)
+			results = append(results, searchResult{form: rank.Target.form, score: score})
+		}
+	}
+
+	for i, result := range results {
+		if result.form.Default {
+			results[i].score += defaultFormBoost
+		}
+		results[i].score += usage(result.form)
+	}
+	slices.SortStableFunc(results, func(a searchResult, b searchResult) int {
+		return cmp.Compare(b.score, a.score)
+	})
+
+	return diversify(results, maxChoices)
+}
+
+// diversify returns up to n forms with at most maxFormsPerSpecies forms per species,
+// remaining slots are filled with the best of the skipped forms.
+func diversify(results []searchResult, n int) []pokeapi.PokemonForm {
+	forms := make([]pokeapi.PokemonForm, 0, min(n, len(results)))
+	var skipped []pokeapi.PokemonForm
+	perSpecies := make(map[string]int)
+	for _, result := range results {
+		if len(forms) >= n {
+			break
+		}
+		if perSpecies[result.form.Species] >= maxFormsPerSpecies {
+			skipped = append(skipped, result.form)
+			continue
+		}
+		perSpecies[result.form.Species]++
+		forms = append(forms, result.form)
+	}
+	for _, form := range skipped {
+		if len(forms) >= n {
+			break
+		}
+		forms = append(forms, form)
+	}
+	return forms
+}
@@ -0,0 +1,154 @@
+package pogoicons
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/icongen"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+func testForm(value string, species string, isDefault bool) pokeapi.PokemonForm {
+	return pokeapi.PokemonForm{
+		Name:    value,
+		Value:   value,
+		Species: species,
+		Default: isDefault,
+	}
+}
+
+func formValues(forms []pokeapi.PokemonForm) []string {
+	values := make([]string, 0, len(forms))
+	for _, form := range forms {
+		values = append(values, form.Value)
+	}
+	return values
+}
+
+func noUsage(pokeapi.PokemonForm) float64 {
+	return 0
+}
+
+func TestSearchPokemon(t *testing.T) {
+	forms := []pokeapi.PokemonForm{
+		testForm("pikachu-rock-star", "pikachu", false),
+		testForm("pikachu", "pikachu", true),
+		testForm("pichu", "pichu", true),
+		testForm("raichu", "raichu", true),
+		testForm("charizard", "charizard", true),
+	}
+	forms[4].Names = map[string]string{"de": "Glurak"}
+
+	tests := []struct {
+		name  string
+		query string
+		usage func(form pokeapi.PokemonForm) float64
+		want  string
+	}{
+		{name: "exact match", query: "pikachu", usage: noUsage, want: "pikachu"},
+		{name: "localized name", query: "glurak", usage: noUsage, want: "charizard"},
+		{name: "prefix match", query: "rai", usage: noUsage, want: "raichu"},
+		{
+			name:  "usage",
+			query: "chu",
+			usage: func(form pokeapi.PokemonForm) float64 {
+				if form.Value == "pichu" {
+					return 100
+				}
+				return 0
+			},
+			want: "pichu",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searchPokemon(forms, tt.query, tt.usage)
+			if len(got) == 0 || got[0].Value != tt.want {
+				t.Fatalf("got %v, want %q first", formValues(got), tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchPokemonDiversify(t *testing.T) {
+	var forms []pokeapi.PokemonForm
+	for i := range maxChoices {
+		forms = append(forms, testForm(fmt.Sprintf("pikachu-%d", i), "pikachu", i == 0))
+	}
+	forms = append(forms, testForm("pichu", "pichu", true))
+
+	got := searchPokemon(forms, "pi", noUsage)
+	if len(got) != maxChoices {
+		t.Fatalf("got %d forms, want %d", len(got), maxChoices)
+	}
+	// pichu ranks below all pikachu forms but is moved up as only maxFormsPerSpecies forms come first
+	if i := slices.Index(formValues(got), "pichu"); i == -1 || i > maxFormsPerSpecies {
+		t.Fatalf("got pichu at %d in %v, want it within the first %d", i, formValues(got), maxFormsPerSpecies+1)
+	}
+}
+
+func TestUsageScore(t *testing.T) {
+	now := time.Now()
+	if score := usageScore(database.Usage{}, now); score != 0 {
+		t.Fatalf("got score %f for unused pokemon, want 0", score)
+	}
+	recent := usageScore(database.Usage{Count: 1, LastUsed: now}, now)
+	old := usageScore(database.Usage{Count: 1, LastUsed: now.Add(-4 * usageHalfLife)}, now)
+	frequent := usageScore(database.Usage{Count: 10, LastUsed: now.Add(-4 * usageHalfLife)}, now)
+	if recent <= old {
+		t.Fatalf("got recent score %f <= old score %f", recent, old)
+	}
+	if frequent <= old {
+		t.Fatalf("got frequent score %f <= old score %f", frequent, old)
+	}
+}
+
+func TestSearchConfig(t *testing.T) {
+	events := []icongen.EventConfig{
+		{Name: "Community Day", Category: "Community", Tags: []string{"monthly"}},
+		{Name: "Spotlight Hour", Category: "Weekly", Tags: []string{"community"}},
+		{Name: "Raid Day", Category: "Raids"},
+		{Name: "Raid Hour", Category: "Raids", Tags: []string{"weekly"}},
+	}
+	terms := eventTerms(events)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{name: "empty query keeps config order", query: "", want: []string{"Community Day", "Spotlight Hour", "Raid Day", "Raid Hour"}},
+		{name: "exact name", query: "raid hour", want: []string{"Raid Hour"}},
+		{name: "prefix", query: "spot", want: []string{"Spotlight Hour"}},
+		{name: "category", query: "raids", want: []string{"Raid Day", "Raid Hour"}},
+		{name: "tag", query: "monthly", want: []string{"Community Day"}},
+		{name: "name ranks above tag", query: "community", want: []string{"Community Day", "Spotlight Hour"}},
+		{name: "no match", query: "xyz", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := searchConfig(terms, tt.query)
+			if len(got) < len(tt.want) || !slices.Equal(got[:len(tt.want)], tt.want) {
+				t.Fatalf("got %v, want %v first", got, tt.want)
+			}
+			if len(tt.want) == 0 && len(got) != 0 {
+				t.Fatalf("got %v, want no matches", got)
+			}
+		})
+	}
+}
+
+func TestSearchConfigCosmetics(t *testing.T) {
+	cosmetics := []icongen.CosmeticConfig{
+		{Name: "Party Hat", Slot: "top"},
+		{Name: "Sunglasses", Slot: "face"},
+		{Name: "Crown", Slot: "top"},
+	}
+	got := searchConfig(cosmeticTerms(cosmetics), "top")
+	if want := []string{"Party Hat", "Crown"}; !slices.Equal(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,71 @@
+package pogoicons
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"time"
+
+	"github.com/disgoorg/snowflake/v2"
+
+	"github.com/topi314/pogo-icons/internal/database"
+	"github.com/topi314/pogo-icons/internal/pokeapi"
+)
+
+const (
+	// maxUsagePerScope is the number of Pokémon remembered per user or guild, the least recently used are dropped first.
+	maxUsagePerScope = 200
+	// usageHalfLife is the time after which the recency boost of a Pokémon halves.
+	usageHalfLife = 6 * time.Hour
+)
+
+// usageScore returns how popular and recent the Pokémon is.
+func usageScore(u database.Usage, now time.Time) float64 {
+	if u.Count == 0 {
+		return 0
+	}
+	popularity := math.Log2(1 + float64(u.Count))
+	recency := math.Exp2(-float64(now.Sub(u.LastUsed)) / float64(usageHalfLife))
+	return popularity + 2*recency
+}
+
+// usage returns the usage of the scope, errors are logged and treated as no usage.
+func (b *Bot) usage(ctx context.Context, scope snowflake.ID) map[string]database.Usage {
+	usage, err := b.db.Usage(scope)
+	if err != nil {
+		slog.ErrorContext(ctx, "error getting usage", slog.Any("err", err))
+	}
+	return usage
+}
+
+// usageScore returns a function which scores forms by how often and how recently the user and guild generated them.
+func (b *Bot) usageScore(ctx context.Context, userID snowflake.ID, guildID *snowflake.ID) func(form pokeapi.PokemonForm) float64 {
+	now := time.Now()
+	userUsage := b.usage(ctx, userID)
+	var guildUsage map[string]database.Usage
+	if guildID != nil {
+		guildUsage = b.usage(ctx, *guildID)
+	}
+	return func(form pokeapi.PokemonForm) float64 {
+		return userUsageBoost*usageScore(userUsage[form.Value], now) + guildUsageBoost*usageScore(guildUsage[form.Value], now)
+	}
+}
+
+// recordUsage remembers the generated Pokémon for the user and guild.
+func (b *Bot) recordUsage(ctx context.Context, userID snowflake.ID, guildID *snowflake.ID, pokemon []string) {
+	scopes := []snowflake.ID{userID}
+	if guildID != nil {
+		scopes = append(scopes, *guildID)
+	}
+	values := make([]string, 0, len(pokemon))
+	for _, p := range pokemon {
+		value, _ := pokeapi.ParsePokemonName(p)
+		values = append(values, value)
+	}
+	now := time.Now()
+	for _, scope := range scopes {
+		if err := b.db.RecordUsage(scope, values, now, maxUsagePerScope); err != nil {
+			slog.ErrorContext(ctx, "error recording usage", slog.Any("err", err))
+		}
+	}
+}